@@ -3,45 +3,148 @@ package cache
 
 import (
 	"sync"
+	"time"
 
 	"github.com/KarolosLykos/hackertea/internal/item"
 )
 
+const (
+	// youngAge is the age under which items are considered young.
+	youngAge = time.Hour
+	// youngTTL is how long young items are fresh. Their scores and replies change quickly.
+	youngTTL = time.Minute
+	// oldTTL is how long older items are fresh.
+	oldTTL = 15 * time.Minute
+	// feedTTL is how long the story IDs of a feed are fresh. Front pages change every minute or so.
+	feedTTL = time.Minute
+)
+
 // Cache is an interface for a cache.
 type Cache interface {
-	Get(key int) (*item.Item, bool)
-	Set(key int, value *item.Item)
+	Get(key int) (Entry, bool)
+	Set(key int, e Entry)
+	Delete(key int)
+}
+
+// FeedCache is an interface for a cache of the story IDs of the feeds, such as the top stories.
+type FeedCache interface {
+	GetFeed(name string) (FeedEntry, bool)
+	SetFeed(name string, e FeedEntry)
+}
+
+// FeedEntry is the list of story IDs of a feed, along with the time it was fetched at.
+type FeedEntry struct {
+	IDs      []int     `json:"ids"`
+	StoredAt time.Time `json:"storedAt"`
+}
+
+// Expired reports whether the feed is no longer fresh at the given time.
+// Expired feeds can still be used while they are fetched again.
+func (e FeedEntry) Expired(now time.Time) bool {
+	return now.Sub(e.StoredAt) > feedTTL
+}
+
+// Entry is an item stored in a cache, along with the time it was fetched at.
+type Entry struct {
+	Item     *item.Item `json:"item"`
+	StoredAt time.Time  `json:"storedAt"`
+}
+
+// NewEntry returns an entry for the given item, fetched at the given time.
+func NewEntry(value *item.Item, storedAt time.Time) Entry {
+	return Entry{Item: value, StoredAt: storedAt}
+}
+
+// TTL returns how long the entry is fresh: items posted less than an hour ago
+// expire sooner than older ones.
+func (e Entry) TTL() time.Duration {
+	if e.StoredAt.Sub(e.Item.Time()) < youngAge {
+		return youngTTL
+	}
+
+	return oldTTL
+}
+
+// Expired reports whether the entry is no longer fresh at the given time.
+// Expired entries can still be used while they are fetched again.
+func (e Entry) Expired(now time.Time) bool {
+	return now.Sub(e.StoredAt) > e.TTL()
+}
+
+// Stats describes how a cache has been used.
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+	// Bytes approximates the memory used by the entries.
+	Bytes int64
+}
+
+// Reporter is implemented by caches that keep usage statistics.
+type Reporter interface {
+	Stats() Stats
 }
 
 // MemCache is an implementation of the Cache interface that stores data in memory.
 type MemCache struct {
-	lock  sync.Mutex
-	items map[int]*item.Item
+	lock   sync.Mutex
+	items  map[int]Entry
+	hits   uint64
+	misses uint64
+	bytes  int64
 }
 
 // New returns a new MemCache.
 func New() *MemCache {
-	return &MemCache{items: make(map[int]*item.Item)}
+	return &MemCache{items: make(map[int]Entry)}
 }
 
-// Get retrieves an item from the cache with the given key.
-// Returns the item and a bool indicating whether the item was found.
-func (m *MemCache) Get(key int) (*item.Item, bool) {
+// Get retrieves an entry from the cache with the given key.
+// Returns the entry and a bool indicating whether the entry was found.
+func (m *MemCache) Get(key int) (Entry, bool) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
 	v, ok := m.items[key]
 	if !ok {
-		return nil, false
+		m.misses++
+		return Entry{}, false
 	}
 
+	m.hits++
+
 	return v, true
 }
 
-// Set sets an item in the cache with the given key and value.
-func (m *MemCache) Set(key int, value *item.Item) {
+// Set sets an entry in the cache with the given key.
+func (m *MemCache) Set(key int, e Entry) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if old, ok := m.items[key]; ok {
+		m.bytes -= entrySize(old)
+	}
+
+	m.items[key] = e
+	m.bytes += entrySize(e)
+}
+
+// Delete removes the item with the given key from the cache.
+func (m *MemCache) Delete(key int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if old, ok := m.items[key]; ok {
+		m.bytes -= entrySize(old)
+		delete(m.items, key)
+	}
+}
+
+// Stats returns the usage statistics of the cache.
+func (m *MemCache) Stats() Stats {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
-	m.items[key] = value
+	return Stats{Hits: m.hits, Misses: m.misses, Entries: len(m.items), Bytes: m.bytes}
 }
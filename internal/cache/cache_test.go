@@ -2,6 +2,7 @@ package cache
 
 import (
 	"testing"
+	"time"
 
 	"github.com/KarolosLykos/hackertea/internal/item"
 )
@@ -9,14 +10,14 @@ import (
 func TestCache_Get(t *testing.T) {
 	c := New()
 
-	c.Set(1, &item.Item{ID: 1})
+	c.Set(1, NewEntry(&item.Item{ID: 1}, time.Now()))
 
 	v, ok := c.Get(1)
 	if !ok {
 		t.Errorf("should find the key")
 	}
 
-	if v.ID != 1 {
+	if v.Item.ID != 1 {
 		t.Errorf("the value should be 1")
 	}
 }
@@ -24,7 +25,7 @@ func TestCache_Get(t *testing.T) {
 func TestCache_Set(t *testing.T) {
 	c := New()
 
-	c.Set(1, &item.Item{ID: 1})
+	c.Set(1, NewEntry(&item.Item{ID: 1}, time.Now()))
 
 	_, ok := c.Get(1)
 	if !ok {
@@ -36,3 +37,71 @@ func TestCache_Set(t *testing.T) {
 		t.Errorf("should not be present")
 	}
 }
+
+func TestCache_Delete(t *testing.T) {
+	c := New()
+
+	c.Set(1, NewEntry(&item.Item{ID: 1}, time.Now()))
+	c.Delete(1)
+
+	_, ok := c.Get(1)
+	if ok {
+		t.Errorf("should not be present")
+	}
+
+	// Deleting a missing key is a no-op.
+	c.Delete(2)
+}
+
+func TestEntry_Expired(t *testing.T) {
+	now := time.Now()
+
+	tt := []struct {
+		name     string
+		posted   time.Time
+		storedAt time.Time
+		expired  bool
+	}{
+		{name: "young and fresh", posted: now.Add(-10 * time.Minute), storedAt: now.Add(-30 * time.Second)},
+		{name: "young and expired", posted: now.Add(-10 * time.Minute), storedAt: now.Add(-2 * time.Minute), expired: true},
+		{name: "old and fresh", posted: now.Add(-24 * time.Hour), storedAt: now.Add(-2 * time.Minute)},
+		{name: "old and expired", posted: now.Add(-24 * time.Hour), storedAt: now.Add(-time.Hour), expired: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			e := NewEntry(&item.Item{ID: 1, Timestamp: int(tc.posted.Unix())}, tc.storedAt)
+
+			if e.Expired(now) != tc.expired {
+				t.Errorf("expired should be %t", tc.expired)
+			}
+		})
+	}
+}
+
+func TestFeedEntry_Expired(t *testing.T) {
+	now := time.Now()
+
+	if (FeedEntry{StoredAt: now.Add(-30 * time.Second)}).Expired(now) {
+		t.Errorf("should be fresh")
+	}
+
+	if !(FeedEntry{StoredAt: now.Add(-2 * time.Minute)}).Expired(now) {
+		t.Errorf("should be expired")
+	}
+}
+
+func TestCache_Stats(t *testing.T) {
+	c := New()
+
+	c.Set(1, NewEntry(&item.Item{ID: 1}, time.Now()))
+	c.Set(2, NewEntry(&item.Item{ID: 2, Text: "text"}, time.Now()))
+	c.Get(1)
+	c.Get(3)
+	c.Delete(2)
+
+	s := c.Stats()
+	if s.Hits != 1 || s.Misses != 1 || s.Entries != 1 || s.Bytes != entryOverhead {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
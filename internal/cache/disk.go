@@ -0,0 +1,348 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/adrg/xdg"
+)
+
+const (
+	// diskDir is where items are stored, relative to the XDG cache directory.
+	diskDir = "hackertea/items"
+	// feedsDir is where feeds are stored, relative to the directory of the cache.
+	feedsDir = "feeds"
+)
+
+// DiskCache is an implementation of the Cache and FeedCache interfaces that stores every entry
+// in its own JSON file, so that items and feeds outlive the application.
+// Entries are written in the background, in batches, so that storing them never holds up a fetch;
+// until then they are served from memory. Close writes the ones still pending.
+// Files are written to a temporary file first and then renamed, so readers,
+// including other instances of the application, never see a partially written item.
+// Files that cannot be decoded are removed and reported as missing.
+type DiskCache struct {
+	dir      string
+	maxAge   time.Duration
+	maxBytes int64
+
+	lock sync.Mutex
+	// pending holds the entries waiting to be written, by file, and writing the ones being written.
+	// A nil entry stands for a file to remove.
+	pending map[string]any
+	writing map[string]any
+	closed  bool
+
+	wake    chan struct{}
+	flushes chan chan struct{}
+	quit    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// diskFile is an item file found while pruning the cache.
+type diskFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// DefaultDiskDir returns the directory of the disk cache under the XDG cache directory.
+func DefaultDiskDir() string {
+	return filepath.Join(xdg.CacheHome, diskDir)
+}
+
+// NewDisk returns a DiskCache storing items in the given directory, creating it if needed.
+// Items last written more than maxAge ago are removed in the background, then the least recently
+// written ones until the items take at most maxBytes bytes. A limit that is not positive is not enforced.
+func NewDisk(dir string, maxAge time.Duration, maxBytes int64) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	d := &DiskCache{
+		dir:      dir,
+		maxAge:   maxAge,
+		maxBytes: maxBytes,
+		pending:  make(map[string]any),
+		wake:     make(chan struct{}, 1),
+		flushes:  make(chan chan struct{}),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	go d.run()
+
+	return d, nil
+}
+
+// Get reads the entry with the given key from the disk.
+// Returns the entry and a bool indicating whether the entry was found.
+func (d *DiskCache) Get(key int) (Entry, bool) {
+	if v, ok := d.unwritten(d.path(key)); ok {
+		e, ok := v.(Entry)
+		return e, ok
+	}
+
+	var e Entry
+	if !readJSON(d.path(key), &e, func() bool { return e.Item != nil && e.Item.ID == key }) {
+		return Entry{}, false
+	}
+
+	return e, true
+}
+
+// Set writes the entry with the given key to the disk.
+// The cache is best effort: an entry that cannot be written is simply fetched again next time.
+func (d *DiskCache) Set(key int, e Entry) {
+	d.queue(d.path(key), e)
+}
+
+// GetFeed reads the feed with the given name from the disk.
+// Returns the feed and a bool indicating whether the feed was found.
+func (d *DiskCache) GetFeed(name string) (FeedEntry, bool) {
+	if v, ok := d.unwritten(d.feedPath(name)); ok {
+		e, ok := v.(FeedEntry)
+		return e, ok
+	}
+
+	var e FeedEntry
+	if !readJSON(d.feedPath(name), &e, func() bool { return !e.StoredAt.IsZero() }) {
+		return FeedEntry{}, false
+	}
+
+	return e, true
+}
+
+// SetFeed writes the feed with the given name to the disk.
+func (d *DiskCache) SetFeed(name string, e FeedEntry) {
+	d.queue(d.feedPath(name), e)
+}
+
+// Delete removes the item with the given key from the disk.
+func (d *DiskCache) Delete(key int) {
+	d.queue(d.path(key), nil)
+}
+
+// Flush waits until the entries set so far are written.
+func (d *DiskCache) Flush() {
+	reply := make(chan struct{})
+
+	select {
+	case d.flushes <- reply:
+		<-reply
+	case <-d.done:
+	}
+}
+
+// Close writes the pending entries and stops the background writer.
+// Entries set afterwards are dropped. Closing a nil DiskCache does nothing.
+func (d *DiskCache) Close() {
+	if d == nil {
+		return
+	}
+
+	d.once.Do(func() {
+		d.lock.Lock()
+		d.closed = true
+		d.lock.Unlock()
+
+		close(d.quit)
+	})
+
+	<-d.done
+}
+
+// queue schedules v to be written to the given file, replacing whatever was scheduled for it.
+func (d *DiskCache) queue(path string, v any) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.closed {
+		return
+	}
+
+	d.pending[path] = v
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// unwritten returns the entry scheduled for the given file, if it is not written yet.
+func (d *DiskCache) unwritten(path string) (any, bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if v, ok := d.pending[path]; ok {
+		return v, true
+	}
+
+	v, ok := d.writing[path]
+
+	return v, ok
+}
+
+// run prunes the cache, then writes the pending entries whenever some are set, until the cache is closed.
+func (d *DiskCache) run() {
+	defer close(d.done)
+
+	d.prune(time.Now())
+
+	for {
+		select {
+		case <-d.wake:
+			d.write()
+		case reply := <-d.flushes:
+			d.write()
+			close(reply)
+		case <-d.quit:
+			d.write()
+			return
+		}
+	}
+}
+
+// write writes the pending entries as one batch.
+func (d *DiskCache) write() {
+	d.lock.Lock()
+	batch := d.pending
+	d.pending, d.writing = make(map[string]any), batch
+	d.lock.Unlock()
+
+	for path, v := range batch {
+		if v == nil {
+			_ = os.Remove(path)
+			continue
+		}
+
+		writeJSON(path, v)
+	}
+
+	d.lock.Lock()
+	d.writing = nil
+	d.lock.Unlock()
+}
+
+// prune removes the items last written before maxAge, then the least recently written ones
+// until they take at most maxBytes. Feeds are always kept.
+func (d *DiskCache) prune(now time.Time) {
+	if d.maxAge <= 0 && d.maxBytes <= 0 {
+		return
+	}
+
+	var (
+		files []diskFile
+		total int64
+	)
+
+	_ = filepath.WalkDir(d.dir, func(path string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if e.IsDir() {
+			if path == filepath.Join(d.dir, feedsDir) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			return nil
+		}
+
+		if d.maxAge > 0 && now.Sub(info.ModTime()) > d.maxAge {
+			_ = os.Remove(path)
+			return nil
+		}
+
+		files = append(files, diskFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+
+		return nil
+	})
+
+	if d.maxBytes <= 0 || total <= d.maxBytes {
+		return
+	}
+
+	slices.SortFunc(files, func(a, b diskFile) int { return a.modTime.Compare(b.modTime) })
+
+	for _, f := range files {
+		if total <= d.maxBytes {
+			return
+		}
+
+		if os.Remove(f.path) == nil {
+			total -= f.size
+		}
+	}
+}
+
+// feedPath returns the file of the feed with the given name.
+func (d *DiskCache) feedPath(name string) string {
+	return filepath.Join(d.dir, feedsDir, filepath.Base(name)+".json")
+}
+
+// path returns the file of the item with the given key.
+// Items are spread over 256 directories to keep them small.
+func (d *DiskCache) path(key int) string {
+	return filepath.Join(d.dir, fmt.Sprintf("%02x", key&0xff), fmt.Sprintf("%d.json", key))
+}
+
+// readJSON decodes the given file into v, and reports whether it succeeded and v is valid.
+// The file is removed if it is corrupt, so that it is fetched again.
+func readJSON(path string, v any, valid func() bool) bool {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	if err = json.Unmarshal(b, v); err != nil || !valid() {
+		_ = os.Remove(path)
+		return false
+	}
+
+	return true
+}
+
+// writeJSON encodes v to the given file. It is written to a temporary file first,
+// then renamed, so that readers never see it partially written.
+func writeJSON(path string, v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return
+	}
+
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+}
@@ -0,0 +1,252 @@
+package cache
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KarolosLykos/hackertea/internal/item"
+)
+
+// newTestDisk returns a DiskCache in the given directory, closed at the end of the test.
+func newTestDisk(t *testing.T, dir string, maxAge time.Duration, maxBytes int64) *DiskCache {
+	t.Helper()
+
+	d, err := NewDisk(dir, maxAge, maxBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(d.Close)
+
+	return d
+}
+
+func TestDiskCache_Get(t *testing.T) {
+	d := newTestDisk(t, t.TempDir(), 0, 0)
+
+	storedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	d.Set(1, NewEntry(&item.Item{ID: 1, Titl: "title", Kids: []int{2, 3}, Visited: true}, storedAt))
+	d.Flush()
+
+	e, ok := d.Get(1)
+	if !ok {
+		t.Fatalf("should find the key")
+	}
+
+	v := e.Item
+	if v.ID != 1 || v.Titl != "title" || len(v.Kids) != 2 {
+		t.Errorf("the item should be stored as it is, got %+v", v)
+	}
+
+	if v.Visited {
+		t.Errorf("the visited state should not be stored")
+	}
+
+	if !e.StoredAt.Equal(storedAt) {
+		t.Errorf("the time the item was stored at should be kept, got %s", e.StoredAt)
+	}
+
+	// Items outlive the cache that wrote them.
+	other := newTestDisk(t, d.dir, 0, 0)
+
+	if _, ok = other.Get(1); !ok {
+		t.Errorf("should find the key written by another cache")
+	}
+
+	if _, ok = d.Get(2); ok {
+		t.Errorf("should not be present")
+	}
+}
+
+func TestDiskCache_Corrupt(t *testing.T) {
+	d := newTestDisk(t, t.TempDir(), 0, 0)
+
+	for key, content := range map[int]string{
+		1: `{"item":{"id":1,"tit`,
+		2: `{"item":{"id":3}}`,
+		3: `{"id":3}`,
+	} {
+		d.Set(key, NewEntry(&item.Item{ID: key}, time.Now()))
+		d.Flush()
+
+		if err := os.WriteFile(d.path(key), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := d.Get(key); ok {
+			t.Errorf("should not return a corrupt item")
+		}
+
+		if _, err := os.Stat(d.path(key)); !os.IsNotExist(err) {
+			t.Errorf("should remove the corrupt file")
+		}
+	}
+}
+
+func TestDiskCache_Feed(t *testing.T) {
+	d := newTestDisk(t, t.TempDir(), 0, 0)
+
+	storedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	d.SetFeed("topstories", FeedEntry{IDs: []int{3, 1, 2}, StoredAt: storedAt})
+	d.Flush()
+
+	e, ok := d.GetFeed("topstories")
+	if !ok {
+		t.Fatalf("should find the feed")
+	}
+
+	if len(e.IDs) != 3 || e.IDs[0] != 3 || !e.StoredAt.Equal(storedAt) {
+		t.Errorf("the feed should be stored as it is, got %+v", e)
+	}
+
+	if _, ok = d.GetFeed("newstories"); ok {
+		t.Errorf("should not be present")
+	}
+
+	// Feeds are kept apart from the items.
+	d.Set(1, NewEntry(&item.Item{ID: 1}, time.Now()))
+	if _, ok = d.Get(1); !ok {
+		t.Errorf("should find the key")
+	}
+
+	if err := os.WriteFile(d.feedPath("topstories"), []byte(`{"ids":[3,`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok = d.GetFeed("topstories"); ok {
+		t.Errorf("should not return a corrupt feed")
+	}
+
+	if _, err := os.Stat(d.feedPath("topstories")); !os.IsNotExist(err) {
+		t.Errorf("should remove the corrupt file")
+	}
+}
+
+func TestDiskCache_Delete(t *testing.T) {
+	d := newTestDisk(t, t.TempDir(), 0, 0)
+
+	d.Set(1, NewEntry(&item.Item{ID: 1}, time.Now()))
+	d.Flush()
+	d.Delete(1)
+
+	if _, ok := d.Get(1); ok {
+		t.Errorf("should not be present")
+	}
+
+	d.Flush()
+
+	if _, err := os.Stat(d.path(1)); !os.IsNotExist(err) {
+		t.Errorf("should remove the file")
+	}
+
+	// Deleting a missing key is a no-op.
+	d.Delete(2)
+}
+
+func TestDiskCache_Concurrent(t *testing.T) {
+	d := newTestDisk(t, t.TempDir(), 0, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(score int) {
+			defer wg.Done()
+			d.Set(1, NewEntry(&item.Item{ID: 1, Score: score}, time.Now()))
+		}(i)
+		go func() {
+			defer wg.Done()
+			// Readers see either no item or a complete one.
+			if e, ok := d.Get(1); ok && e.Item.ID != 1 {
+				t.Errorf("should not see a partially written item")
+			}
+		}()
+	}
+	wg.Wait()
+	d.Flush()
+
+	if _, ok := d.Get(1); !ok {
+		t.Errorf("should find the key")
+	}
+
+	entries, err := os.ReadDir(d.dir + "/01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("should not leave temporary files, got %d files", len(entries))
+	}
+}
+
+func TestDiskCache_Unwritten(t *testing.T) {
+	d := newTestDisk(t, t.TempDir(), 0, 0)
+
+	d.Set(1, NewEntry(&item.Item{ID: 1}, time.Now()))
+	d.SetFeed("topstories", FeedEntry{IDs: []int{1}, StoredAt: time.Now()})
+
+	// Entries are served before they are written.
+	if _, ok := d.Get(1); !ok {
+		t.Errorf("should find the key")
+	}
+
+	if _, ok := d.GetFeed("topstories"); !ok {
+		t.Errorf("should find the feed")
+	}
+
+	// Closing writes them, and drops the ones set afterwards.
+	d.Close()
+	d.Set(2, NewEntry(&item.Item{ID: 2}, time.Now()))
+
+	other := newTestDisk(t, d.dir, 0, 0)
+
+	if _, ok := other.Get(1); !ok {
+		t.Errorf("should write the pending entries when closed")
+	}
+
+	if _, ok := other.Get(2); ok {
+		t.Errorf("should not write entries set once closed")
+	}
+}
+
+func TestDiskCache_Prune(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	d := newTestDisk(t, dir, 0, 0)
+	for key := 1; key <= 4; key++ {
+		d.Set(key, NewEntry(&item.Item{ID: key, Titl: "title"}, now))
+	}
+	d.SetFeed("topstories", FeedEntry{IDs: []int{1, 2, 3, 4}, StoredAt: now})
+	d.Close()
+
+	// Item 1 is too old, item 2 is the least recently written of the others.
+	for key, age := range map[int]time.Duration{1: 48 * time.Hour, 2: 3 * time.Hour, 3: 2 * time.Hour, 4: time.Hour} {
+		if err := os.Chtimes(d.path(key), now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.Chtimes(d.feedPath("topstories"), now.Add(-48*time.Hour), now.Add(-48*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(d.path(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pruned := newTestDisk(t, dir, 24*time.Hour, 2*info.Size())
+	pruned.Flush()
+
+	for key, kept := range map[int]bool{1: false, 2: false, 3: true, 4: true} {
+		if _, err = os.Stat(d.path(key)); os.IsNotExist(err) == kept {
+			t.Errorf("item %d should be kept: %t", key, kept)
+		}
+	}
+
+	if _, ok := pruned.GetFeed("topstories"); !ok {
+		t.Errorf("should keep the feeds")
+	}
+}
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"sync/atomic"
+)
+
+// Layered is an implementation of the Cache interface that stacks a fast cache,
+// such as a MemCache, on top of a slower but larger one, such as a DiskCache.
+type Layered struct {
+	front  Cache
+	back   Cache
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// NewLayered returns a Layered cache looking items up in front first, then in back.
+func NewLayered(front, back Cache) *Layered {
+	return &Layered{front: front, back: back}
+}
+
+// Get retrieves an entry from the front cache, or from the back cache, in which case
+// the entry is copied to the front cache for the next lookups.
+// Returns the entry and a bool indicating whether the entry was found.
+func (l *Layered) Get(key int) (Entry, bool) {
+	if e, ok := l.front.Get(key); ok {
+		l.hits.Add(1)
+		return e, true
+	}
+
+	e, ok := l.back.Get(key)
+	if !ok {
+		l.misses.Add(1)
+		return Entry{}, false
+	}
+
+	l.hits.Add(1)
+	l.front.Set(key, e)
+
+	return e, true
+}
+
+// Set sets an entry in both caches.
+func (l *Layered) Set(key int, e Entry) {
+	l.front.Set(key, e)
+	l.back.Set(key, e)
+}
+
+// Delete removes the item with the given key from both caches.
+func (l *Layered) Delete(key int) {
+	l.front.Delete(key)
+	l.back.Delete(key)
+}
+
+// Stats returns the hits and misses of both caches together,
+// along with the evictions, entries and size of the front cache if it reports them.
+func (l *Layered) Stats() Stats {
+	var s Stats
+	if r, ok := l.front.(Reporter); ok {
+		s = r.Stats()
+	}
+
+	s.Hits, s.Misses = l.hits.Load(), l.misses.Load()
+
+	return s
+}
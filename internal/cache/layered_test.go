@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KarolosLykos/hackertea/internal/item"
+)
+
+func TestLayered_Get(t *testing.T) {
+	front, back := New(), New()
+	l := NewLayered(front, back)
+
+	storedAt := time.Now().Add(-time.Hour)
+	back.Set(1, NewEntry(&item.Item{ID: 1}, storedAt))
+
+	if _, ok := l.Get(1); !ok {
+		t.Fatalf("should find the key in the back cache")
+	}
+
+	e, ok := front.Get(1)
+	if !ok {
+		t.Fatalf("should copy the item to the front cache")
+	}
+
+	if !e.StoredAt.Equal(storedAt) {
+		t.Errorf("should keep the time the item was stored at")
+	}
+
+	if _, ok := l.Get(2); ok {
+		t.Errorf("should not be present")
+	}
+}
+
+func TestLayered_SetDelete(t *testing.T) {
+	front, back := New(), New()
+	l := NewLayered(front, back)
+
+	l.Set(1, NewEntry(&item.Item{ID: 1}, time.Now()))
+
+	_, inFront := front.Get(1)
+	_, inBack := back.Get(1)
+	if !inFront || !inBack {
+		t.Errorf("should be present in both caches")
+	}
+
+	l.Delete(1)
+
+	_, inFront = front.Get(1)
+	_, inBack = back.Get(1)
+	if inFront || inBack {
+		t.Errorf("should be removed from both caches")
+	}
+}
+
+func TestLayered_Stats(t *testing.T) {
+	front, back := NewLRU(10, 0), New()
+	l := NewLayered(front, back)
+
+	back.Set(1, NewEntry(&item.Item{ID: 1}, time.Now()))
+	l.Get(1)
+	l.Get(1)
+	l.Get(2)
+
+	s := l.Stats()
+	if s.Hits != 2 || s.Misses != 1 || s.Entries != 1 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// entryOverhead approximates the memory used by an entry besides its strings and IDs.
+const entryOverhead = 256
+
+// LRU is an implementation of the Cache interface that stores data in memory,
+// up to a number of entries and an approximate size in bytes.
+// Once full, the least recently used entries are evicted first.
+type LRU struct {
+	lock       sync.Mutex
+	maxEntries int
+	maxBytes   int64
+	bytes      int64
+	hits       uint64
+	misses     uint64
+	evictions  uint64
+	order      *list.List
+	items      map[int]*list.Element
+}
+
+// lruEntry is an entry along with its key and its approximate size.
+type lruEntry struct {
+	key   int
+	entry Entry
+	size  int64
+}
+
+// NewLRU returns an LRU holding at most maxEntries entries and about maxBytes bytes.
+// A limit that is not positive is not enforced.
+func NewLRU(maxEntries int, maxBytes int64) *LRU {
+	return &LRU{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		order:      list.New(),
+		items:      make(map[int]*list.Element),
+	}
+}
+
+// Get retrieves an entry from the cache with the given key, marking it as recently used.
+// Returns the entry and a bool indicating whether the entry was found.
+func (l *LRU) Get(key int) (Entry, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	el, ok := l.items[key]
+	if !ok {
+		l.misses++
+		return Entry{}, false
+	}
+
+	l.hits++
+	l.order.MoveToFront(el)
+
+	return el.Value.(*lruEntry).entry, true
+}
+
+// Set sets an entry in the cache with the given key, evicting the least recently used entries
+// if the cache is full.
+func (l *LRU) Set(key int, e Entry) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	size := entrySize(e)
+
+	if el, ok := l.items[key]; ok {
+		le := el.Value.(*lruEntry)
+		l.bytes += size - le.size
+		le.entry, le.size = e, size
+		l.order.MoveToFront(el)
+	} else {
+		l.items[key] = l.order.PushFront(&lruEntry{key: key, entry: e, size: size})
+		l.bytes += size
+	}
+
+	// The entry just set is always kept, even if it is larger than the cache.
+	for l.order.Len() > 1 && l.full() {
+		l.remove(l.order.Back())
+		l.evictions++
+	}
+}
+
+// Delete removes the item with the given key from the cache.
+func (l *LRU) Delete(key int) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if el, ok := l.items[key]; ok {
+		l.remove(el)
+	}
+}
+
+// Len returns the number of entries in the cache.
+func (l *LRU) Len() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.order.Len()
+}
+
+// Stats returns the usage statistics of the cache.
+func (l *LRU) Stats() Stats {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return Stats{
+		Hits:      l.hits,
+		Misses:    l.misses,
+		Evictions: l.evictions,
+		Entries:   l.order.Len(),
+		Bytes:     l.bytes,
+	}
+}
+
+// full reports whether the cache holds more than it is allowed to.
+func (l *LRU) full() bool {
+	return (l.maxEntries > 0 && l.order.Len() > l.maxEntries) ||
+		(l.maxBytes > 0 && l.bytes > l.maxBytes)
+}
+
+func (l *LRU) remove(el *list.Element) {
+	le := l.order.Remove(el).(*lruEntry)
+	delete(l.items, le.key)
+	l.bytes -= le.size
+}
+
+// entrySize approximates the memory used by the given entry.
+func entrySize(e Entry) int64 {
+	if e.Item == nil {
+		return entryOverhead
+	}
+
+	i := e.Item
+
+	return int64(entryOverhead +
+		len(i.By) + len(i.Type) + len(i.Titl) + len(i.Text) + len(i.URL) +
+		8*(len(i.Kids)+len(i.Parts)))
+}
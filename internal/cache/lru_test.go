@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KarolosLykos/hackertea/internal/item"
+)
+
+func TestLRU_MaxEntries(t *testing.T) {
+	l := NewLRU(2, 0)
+
+	l.Set(1, NewEntry(&item.Item{ID: 1}, time.Now()))
+	l.Set(2, NewEntry(&item.Item{ID: 2}, time.Now()))
+
+	// Using 1 makes 2 the least recently used entry.
+	if _, ok := l.Get(1); !ok {
+		t.Fatalf("should find the key")
+	}
+
+	l.Set(3, NewEntry(&item.Item{ID: 3}, time.Now()))
+
+	if _, ok := l.Get(2); ok {
+		t.Errorf("should evict the least recently used entry")
+	}
+
+	for _, key := range []int{1, 3} {
+		if _, ok := l.Get(key); !ok {
+			t.Errorf("should keep %d", key)
+		}
+	}
+
+	if l.Len() != 2 {
+		t.Errorf("should hold 2 entries, got %d", l.Len())
+	}
+}
+
+func TestLRU_MaxBytes(t *testing.T) {
+	text := strings.Repeat("a", 1000)
+	l := NewLRU(0, 3*(entryOverhead+1000))
+
+	for key := 1; key <= 4; key++ {
+		l.Set(key, NewEntry(&item.Item{ID: key, Text: text}, time.Now()))
+	}
+
+	if _, ok := l.Get(1); ok {
+		t.Errorf("should evict entries once too large")
+	}
+
+	if l.Len() != 3 {
+		t.Errorf("should hold 3 entries, got %d", l.Len())
+	}
+
+	// Replacing an entry accounts for its new size.
+	l.Set(4, NewEntry(&item.Item{ID: 4}, time.Now()))
+	l.Set(5, NewEntry(&item.Item{ID: 5}, time.Now()))
+
+	if l.Len() != 4 {
+		t.Errorf("should hold 4 entries, got %d", l.Len())
+	}
+
+	// An entry larger than the cache is kept on its own.
+	l.Set(6, NewEntry(&item.Item{ID: 6, Text: strings.Repeat(text, 10)}, time.Now()))
+
+	if _, ok := l.Get(6); !ok || l.Len() != 1 {
+		t.Errorf("should only keep the last entry, got %d entries", l.Len())
+	}
+}
+
+func TestLRU_Delete(t *testing.T) {
+	l := NewLRU(10, 0)
+
+	l.Set(1, NewEntry(&item.Item{ID: 1}, time.Now()))
+	l.Delete(1)
+
+	if _, ok := l.Get(1); ok {
+		t.Errorf("should not be present")
+	}
+
+	if l.bytes != 0 {
+		t.Errorf("should not account for deleted entries, got %d bytes", l.bytes)
+	}
+
+	// Deleting a missing key is a no-op.
+	l.Delete(2)
+}
+
+func TestLRU_Stats(t *testing.T) {
+	l := NewLRU(1, 0)
+
+	l.Set(1, NewEntry(&item.Item{ID: 1}, time.Now()))
+	l.Set(2, NewEntry(&item.Item{ID: 2}, time.Now()))
+	l.Get(1)
+	l.Get(2)
+
+	s := l.Stats()
+	if s.Hits != 1 || s.Misses != 1 || s.Evictions != 1 || s.Entries != 1 || s.Bytes != entryOverhead {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
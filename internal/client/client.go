@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 )
 
 // HttpClient interface that specifies the behavior of an HTTP client.
 type HttpClient interface {
 	Get(ctx context.Context, suffix string) ([]byte, error)
+	Stream(ctx context.Context, suffix string, handle func(Event) error) error
 }
 
 // Client struct that implements the HttpClient interface.
@@ -18,28 +20,76 @@ type Client struct {
 	c       *http.Client
 }
 
+// Option configures a Client.
+type Option func(*Client)
+
+// maxDrain is the most bytes read from an unsuccessful response to keep its connection alive.
+const maxDrain = 64 << 10
+
+// StatusError is returned when the API answers with a status other than 200 OK.
+type StatusError struct {
+	// Code is the HTTP status code, such as 404.
+	Code int
+	// Status is the HTTP status line, such as "404 Not Found".
+	Status string
+	// URL is the address of the request.
+	URL string
+	// RetryAfter is the delay the server asked to wait before sending the request again, if any.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	if e.Status == "" {
+		return fmt.Sprintf("%d %s", e.Code, http.StatusText(e.Code))
+	}
+
+	return e.Status
+}
+
+// newStatusError returns the error describing an unsuccessful response.
+func newStatusError(res *http.Response) *StatusError {
+	se := &StatusError{
+		Code:       res.StatusCode,
+		Status:     res.Status,
+		RetryAfter: parseRetryAfter(res.Header, time.Now()),
+	}
+
+	if res.Request != nil {
+		se.URL = res.Request.URL.String()
+	}
+
+	return se
+}
+
 // New returns a new Client instance with the given base URL and HTTP client
-func New(baseURL string, c *http.Client) *Client {
-	return &Client{
+func New(baseURL string, c *http.Client, opts ...Option) *Client {
+	client := &Client{
 		baseURL: baseURL,
 		c:       c,
 	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	return client
 }
 
-// Get makes a GET request to the API with the given suffix and returns the response body as a []byte
+// Get makes a GET request to the API with the given suffix and returns the response body as a []byte.
+// Retries and rate limiting are left to the middleware of the client, see WithMiddleware.
 func (c *Client) Get(ctx context.Context, suffix string) ([]byte, error) {
 	// Construct the full URL for the API endpoint.
-	uri := fmt.Sprintf("%s/%s", c.baseURL, suffix)
+	return c.get(ctx, fmt.Sprintf("%s/%s", c.baseURL, suffix))
+}
 
+// get makes a GET request to the given URL.
+func (c *Client) get(ctx context.Context, uri string) ([]byte, error) {
 	// Create a new HTTP request with the given context, method, URL, and body.
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
 	if err != nil {
 		return nil, err // If an error occurred while creating the request, return it
 	}
 
-	// Enable HTTP keep-alive to improve performance.
-	req.Close = true
-
 	res, err := c.c.Do(req)
 	if err != nil {
 		return nil, err
@@ -47,7 +97,10 @@ func (c *Client) Get(ctx context.Context, suffix string) ([]byte, error) {
 	defer res.Body.Close()
 
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%s", res.Status)
+		// Drain what is left of the body so that the connection can be reused.
+		_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, maxDrain))
+
+		return nil, newStatusError(res)
 	}
 
 	resp, err := io.ReadAll(res.Body)
@@ -95,7 +95,10 @@ func TestClient_Get(t *testing.T) {
 
 		_, err := c.Get(ctx, baseURL+path)
 
-		assert.Error(t, err)
+		var se *StatusError
+		require.ErrorAs(t, err, &se)
+		assert.Equal(t, http.StatusInternalServerError, se.Code)
+		assert.EqualError(t, err, "500 Internal Server Error")
 	})
 
 	t.Run("timeout", func(t *testing.T) {
@@ -0,0 +1,153 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// latencyWindow is the number of recent requests the latency percentiles are computed from.
+const latencyWindow = 1024
+
+// Stats describes the requests sent through a Metrics middleware.
+type Stats struct {
+	Requests uint64
+	// Failures counts the requests that failed or were answered with an error status.
+	Failures uint64
+	InFlight int
+	// Bytes is the size of the response bodies read so far.
+	Bytes int64
+	// P50, P90 and P99 are percentiles of the latency of the most recent requests,
+	// from sending them to reading the whole response.
+	P50, P90, P99 time.Duration
+}
+
+// Metrics collects statistics about the requests going through its middleware. It is safe for concurrent use.
+type Metrics struct {
+	mu        sync.Mutex
+	requests  uint64
+	failures  uint64
+	inFlight  int
+	bytes     int64
+	latencies []time.Duration
+	next      int
+}
+
+// NewMetrics returns an empty Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{latencies: make([]time.Duration, 0, latencyWindow)}
+}
+
+// Middleware returns the middleware recording the requests sent through it.
+// Server-sent events streams are left out, since they stay open as long as they are watched.
+func (m *Metrics) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if isStream(r) {
+				return next.RoundTrip(r)
+			}
+
+			start := time.Now()
+
+			m.mu.Lock()
+			m.requests++
+			m.inFlight++
+			m.mu.Unlock()
+
+			res, err := next.RoundTrip(r)
+			if err != nil {
+				m.done(start, true)
+				return nil, err
+			}
+
+			// The request is over once its body is closed.
+			res.Body = &meteredBody{
+				ReadCloser: res.Body,
+				metrics:    m,
+				start:      start,
+				failed:     res.StatusCode >= http.StatusBadRequest,
+			}
+
+			return res, nil
+		})
+	}
+}
+
+// Stats returns the statistics collected so far.
+func (m *Metrics) Stats() Stats {
+	m.mu.Lock()
+	s := Stats{Requests: m.requests, Failures: m.failures, InFlight: m.inFlight, Bytes: m.bytes}
+	latencies := slices.Clone(m.latencies)
+	m.mu.Unlock()
+
+	if len(latencies) == 0 {
+		return s
+	}
+
+	slices.Sort(latencies)
+	s.P50 = percentile(latencies, 50)
+	s.P90 = percentile(latencies, 90)
+	s.P99 = percentile(latencies, 99)
+
+	return s
+}
+
+// done records the end of a request started at the given time.
+func (m *Metrics) done(start time.Time, failed bool) {
+	latency := time.Since(start)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight--
+	if failed {
+		m.failures++
+	}
+
+	if len(m.latencies) < latencyWindow {
+		m.latencies = append(m.latencies, latency)
+		return
+	}
+
+	m.latencies[m.next] = latency
+	m.next = (m.next + 1) % latencyWindow
+}
+
+func (m *Metrics) read(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.bytes += int64(n)
+}
+
+// percentile returns the p-th percentile of the given sorted durations, using the nearest rank.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+
+	return sorted[max(rank-1, 0)]
+}
+
+// meteredBody counts the bytes read from a response body and ends its request when closed.
+type meteredBody struct {
+	io.ReadCloser
+	metrics *Metrics
+	start   time.Time
+	failed  bool
+	once    sync.Once
+}
+
+func (b *meteredBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.metrics.read(n)
+
+	return n, err
+}
+
+func (b *meteredBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.metrics.done(b.start, b.failed) })
+
+	return err
+}
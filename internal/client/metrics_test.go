@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer ts.Close()
+
+	m := NewMetrics()
+	c := New(ts.URL, ts.Client(), WithMiddleware(m.Middleware()))
+
+	for i := 0; i < 3; i++ {
+		_, err := c.Get(context.Background(), "item.json")
+		require.NoError(t, err)
+	}
+
+	_, err := c.Get(context.Background(), "missing.json")
+	require.Error(t, err)
+
+	s := m.Stats()
+	assert.Equal(t, uint64(4), s.Requests)
+	assert.Equal(t, uint64(1), s.Failures)
+	assert.Zero(t, s.InFlight)
+	assert.Equal(t, int64(3*len(`{"id":1}`)), s.Bytes)
+	assert.Positive(t, s.P50)
+	assert.LessOrEqual(t, s.P50, s.P90)
+	assert.LessOrEqual(t, s.P90, s.P99)
+}
+
+func TestMetrics_TransportError(t *testing.T) {
+	m := NewMetrics()
+	failing := RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+
+	c := New("http://test.com", &http.Client{Transport: Chain(failing, m.Middleware())})
+
+	_, err := c.Get(context.Background(), "item.json")
+	require.Error(t, err)
+
+	s := m.Stats()
+	assert.Equal(t, uint64(1), s.Requests)
+	assert.Equal(t, uint64(1), s.Failures)
+	assert.Zero(t, s.InFlight)
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	assert.Equal(t, 50*time.Millisecond, percentile(sorted, 50))
+	assert.Equal(t, 90*time.Millisecond, percentile(sorted, 90))
+	assert.Equal(t, 99*time.Millisecond, percentile(sorted, 99))
+	assert.Equal(t, time.Second, percentile([]time.Duration{time.Second}, 50))
+}
+
+func TestMetrics_Stream(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("event: put\ndata: {\"path\":\"/\",\"data\":[1]}\n\n"))
+	}))
+	defer ts.Close()
+
+	m := NewMetrics()
+	c := New(ts.URL, ts.Client(), WithMiddleware(m.Middleware()))
+
+	err := c.Stream(context.Background(), "topstories.json", func(Event) error { return nil })
+	require.NoError(t, err)
+
+	// Streams stay open as long as they are watched, so they would skew the latencies.
+	s := m.Stats()
+	assert.Zero(t, s.Requests)
+	assert.Zero(t, s.InFlight)
+	assert.Zero(t, s.P50)
+}
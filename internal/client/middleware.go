@@ -0,0 +1,140 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// Middleware wraps a RoundTripper to extend what happens around every request sent by a client.
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts an ordinary function to the http.RoundTripper interface.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(r).
+func (f RoundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// Chain wraps rt with the given middleware, the first one being the outermost.
+// A nil rt stands for http.DefaultTransport.
+func Chain(rt http.RoundTripper, mws ...Middleware) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	for i := len(mws) - 1; i >= 0; i-- {
+		rt = mws[i](rt)
+	}
+
+	return rt
+}
+
+// WithMiddleware makes the client send its requests through the given middleware.
+// The HTTP client given to New is left untouched.
+func WithMiddleware(mws ...Middleware) Option {
+	return func(c *Client) {
+		hc := *c.c
+		hc.Transport = Chain(hc.Transport, mws...)
+		c.c = &hc
+	}
+}
+
+// UserAgent sets the User-Agent header of every request.
+func UserAgent(ua string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			// A RoundTripper must not modify the request it is given.
+			r = r.Clone(r.Context())
+			r.Header.Set("User-Agent", ua)
+
+			return next.RoundTrip(r)
+		})
+	}
+}
+
+// Timeout cancels every request that takes longer than d, reading its body included.
+// Placed after Retry in the chain, every attempt gets its own timeout.
+// Server-sent events streams are left alone, since they are meant to stay open.
+func Timeout(d time.Duration) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if d <= 0 || isStream(r) {
+				return next.RoundTrip(r)
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+
+			res, err := next.RoundTrip(r.WithContext(ctx))
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+
+			res.Body = &cancelBody{ReadCloser: res.Body, cancel: cancel}
+
+			return res, nil
+		})
+	}
+}
+
+// cancelBody cancels the context of its request once it is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+
+	return b.ReadCloser.Close()
+}
+
+// Logging logs every request with its status and how long it took.
+func Logging(l *slog.Logger) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			start := time.Now()
+			res, err := next.RoundTrip(r)
+
+			attrs := []any{"method", r.Method, "url", r.URL.String(), "duration", time.Since(start)}
+			if err != nil {
+				l.Error("request failed", append(attrs, "error", err)...)
+				return res, err
+			}
+
+			l.Info("request", append(attrs, "status", res.StatusCode)...)
+
+			return res, nil
+		})
+	}
+}
+
+// FaultInjection fails the given fraction of requests, between 0 and 1,
+// with a 503 Service Unavailable response without sending them.
+// It is meant to check how the application copes with an unreliable API.
+func FaultInjection(rate float64) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if rand.Float64() >= rate {
+				return next.RoundTrip(r)
+			}
+
+			return &http.Response{
+				Status:     "503 Service Unavailable (injected)",
+				StatusCode: http.StatusServiceUnavailable,
+				Proto:      r.Proto,
+				ProtoMajor: r.ProtoMajor,
+				ProtoMinor: r.ProtoMinor,
+				Header:     http.Header{},
+				Body:       io.NopCloser(bytes.NewReader(nil)),
+				Request:    r,
+			}, nil
+		})
+	}
+}
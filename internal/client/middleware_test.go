@@ -0,0 +1,122 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain(t *testing.T) {
+	var order []string
+
+	record := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.RoundTrip(r)
+			})
+		}
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer ts.Close()
+
+	hc := ts.Client()
+	transport := hc.Transport
+
+	c := New(ts.URL, hc, WithMiddleware(record("first"), record("second")))
+
+	_, err := c.Get(context.Background(), "item.json")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"first", "second"}, order)
+	assert.Equal(t, transport, hc.Transport, "the given HTTP client is left untouched")
+}
+
+func TestUserAgent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.UserAgent()))
+	}))
+	defer ts.Close()
+
+	c := New(ts.URL, ts.Client(), WithMiddleware(UserAgent("hackertea")))
+
+	resp, err := c.Get(context.Background(), "item.json")
+	require.NoError(t, err)
+
+	assert.Equal(t, "hackertea", string(resp))
+}
+
+func TestLogging(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	c := New(ts.URL, ts.Client(), WithMiddleware(Logging(slog.New(slog.NewTextHandler(&buf, nil)))))
+
+	_, err := c.Get(context.Background(), "item.json")
+	require.Error(t, err)
+
+	assert.Contains(t, buf.String(), "url="+ts.URL+"/item.json")
+	assert.Contains(t, buf.String(), "status=404")
+}
+
+func TestFaultInjection(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		requests.Add(1)
+	}))
+	defer ts.Close()
+
+	t.Run("fails every request", func(t *testing.T) {
+		c := New(ts.URL, ts.Client(), WithMiddleware(FaultInjection(1)))
+
+		_, err := c.Get(context.Background(), "item.json")
+
+		assert.EqualError(t, err, "503 Service Unavailable (injected)")
+		assert.Zero(t, requests.Load())
+	})
+
+	t.Run("fails no request", func(t *testing.T) {
+		c := New(ts.URL, ts.Client(), WithMiddleware(FaultInjection(0)))
+
+		_, err := c.Get(context.Background(), "item.json")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), requests.Load())
+	})
+}
+
+func TestTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow.json" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}
+
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	c := New(ts.URL, ts.Client(), WithMiddleware(Timeout(50*time.Millisecond)))
+
+	// The body is still read once the response arrived in time.
+	resp, err := c.Get(context.Background(), "item.json")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(resp))
+
+	_, err = c.Get(context.Background(), "slow.json")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket rate limiter, safe for concurrent use.
+// The bucket holds up to burst tokens and is refilled at a steady rate;
+// every request takes a token, waiting for one to become available if the bucket is empty.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewLimiter returns a limiter allowing rps requests per second on average,
+// with bursts of up to burst requests. It returns nil, which never waits, if rps is not positive.
+func NewLimiter(rps float64, burst int) *Limiter {
+	if rps <= 0 {
+		return nil
+	}
+
+	b := math.Max(float64(burst), 1)
+
+	return &Limiter{
+		rate:   rps,
+		burst:  b,
+		tokens: b,
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// RateLimit makes every request wait for the given limiter before it is sent.
+// Clients sharing a limiter share its rate.
+func RateLimit(l *Limiter) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if err := l.Wait(r.Context()); err != nil {
+				return nil, err
+			}
+
+			return next.RoundTrip(r)
+		})
+	}
+}
+
+// Wait blocks until a request is allowed or the context is done.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d := l.reserve()
+	if d <= 0 {
+		return nil
+	}
+
+	if err := sleep(ctx, d); err != nil {
+		// The request was not sent, so its token is given back.
+		l.release()
+		return err
+	}
+
+	return nil
+}
+
+// reserve takes a token and returns how long to wait until it is actually available.
+func (l *Limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill()
+	l.tokens--
+
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// release gives back a token taken by reserve.
+func (l *Limiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill()
+	l.tokens = math.Min(l.tokens+1, l.burst)
+}
+
+// refill adds the tokens earned since the last call, up to the size of the bucket.
+func (l *Limiter) refill() {
+	now := l.now()
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens = math.Min(l.tokens+elapsed.Seconds()*l.rate, l.burst)
+		l.last = now
+	}
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLimiter(t *testing.T) {
+	t.Run("disabled without a rate", func(t *testing.T) {
+		l := NewLimiter(0, 10)
+
+		assert.Nil(t, l)
+		assert.NoError(t, l.Wait(context.Background()))
+	})
+
+	t.Run("keeps a burst of at least one", func(t *testing.T) {
+		l := NewLimiter(1, 0)
+
+		assert.Equal(t, 1.0, l.burst)
+	})
+}
+
+func TestLimiter_Reserve(t *testing.T) {
+	now := time.Now()
+	l := NewLimiter(10, 2)
+	l.last = now
+	l.now = func() time.Time { return now }
+
+	// The burst is available right away.
+	assert.Zero(t, l.reserve())
+	assert.Zero(t, l.reserve())
+
+	// Then requests are spaced by 1/rate.
+	assert.Equal(t, 100*time.Millisecond, l.reserve())
+	assert.Equal(t, 200*time.Millisecond, l.reserve())
+
+	// Giving a token back shortens the wait of the next request.
+	l.release()
+	assert.Equal(t, 200*time.Millisecond, l.reserve())
+
+	// Tokens are earned back over time, up to the burst.
+	now = now.Add(time.Hour)
+	assert.Zero(t, l.reserve())
+	assert.Zero(t, l.reserve())
+	assert.Equal(t, 100*time.Millisecond, l.reserve())
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	t.Run("canceled context", func(t *testing.T) {
+		l := NewLimiter(1, 1)
+		require.NoError(t, l.Wait(context.Background()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, l.Wait(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("shared by concurrent clients", func(t *testing.T) {
+		var requests atomic.Int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests.Add(1)
+		}))
+		defer ts.Close()
+
+		l := NewLimiter(100, 5)
+		clients := []*Client{
+			New(ts.URL, ts.Client(), WithMiddleware(RateLimit(l))),
+			New(ts.URL, ts.Client(), WithMiddleware(RateLimit(l))),
+		}
+
+		start := time.Now()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(c *Client) {
+				defer wg.Done()
+				_, err := c.Get(context.Background(), "item.json")
+				assert.NoError(t, err)
+			}(clients[i%len(clients)])
+		}
+		wg.Wait()
+
+		// The first 5 requests use the burst, the other 5 are spaced by 10ms.
+		assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+		assert.Equal(t, int32(10), requests.Load())
+	})
+}
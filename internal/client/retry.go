@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy describes how failed requests are retried.
+// Requests are retried on 429 and 5xx responses and on transient network errors,
+// waiting an exponentially growing, jittered delay between attempts.
+// A Retry-After header sent by the server takes precedence over the computed delay.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt. Zero disables retries.
+	MaxRetries int
+	// BaseDelay is the delay before the first retry, doubled on every attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two attempts.
+	MaxDelay time.Duration
+}
+
+// Retry sends the failed requests again according to the given policy, as long as their context allows it.
+// Requests with a body are not retried, since it can't be sent twice.
+// Placed before RateLimit in the chain, every attempt waits for the limiter.
+func Retry(p RetryPolicy) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			ctx := r.Context()
+
+			for attempt := 0; ; attempt++ {
+				res, err := next.RoundTrip(r)
+				if attempt >= p.MaxRetries || (r.Body != nil && r.Body != http.NoBody) || !retryable(ctx, res, err) {
+					return res, err
+				}
+
+				var retryAfter time.Duration
+				if res != nil {
+					retryAfter = parseRetryAfter(res.Header, time.Now())
+
+					// Drain what is left of the body so that the connection can be reused.
+					_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, maxDrain))
+					res.Body.Close()
+				}
+
+				if err = sleep(ctx, p.delay(attempt, retryAfter)); err != nil {
+					return nil, err
+				}
+			}
+		})
+	}
+}
+
+// delay returns how long to wait before the given retry attempt, starting at zero.
+// The delay is drawn uniformly up to the exponential backoff ("full jitter"),
+// unless the server asked for a specific delay.
+func (p RetryPolicy) delay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return p.capped(retryAfter)
+	}
+
+	backoff := p.BaseDelay << attempt
+	if attempt > 62 || backoff>>attempt != p.BaseDelay {
+		// The backoff overflowed.
+		backoff = math.MaxInt64
+	}
+
+	backoff = p.capped(backoff)
+	if backoff <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int64N(int64(backoff) + 1))
+}
+
+// capped limits d to MaxDelay.
+func (p RetryPolicy) capped(d time.Duration) time.Duration {
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		return p.MaxDelay
+	}
+
+	return d
+}
+
+// retryable reports whether a request that got res or failed with err may succeed if sent again.
+func retryable(ctx context.Context, res *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err == nil {
+		return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError
+	}
+
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// parseRetryAfter returns the delay requested by a Retry-After header,
+// given either in seconds or as an HTTP date. It returns zero if there is none.
+func parseRetryAfter(h http.Header, now time.Time) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+
+	return 0
+}
+
+// sleep waits for d or until the context is done, whichever happens first.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+	t.Run("succeeds after server errors", func(t *testing.T) {
+		var calls atomic.Int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			_, _ = w.Write([]byte("ok"))
+		}))
+		defer ts.Close()
+
+		c := New(ts.URL, &http.Client{}, WithMiddleware(Retry(policy)))
+
+		resp, err := c.Get(context.Background(), "test")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("ok"), resp)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		var calls atomic.Int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer ts.Close()
+
+		c := New(ts.URL, &http.Client{}, WithMiddleware(Retry(policy)))
+
+		_, err := c.Get(context.Background(), "test")
+		assert.ErrorContains(t, err, "429")
+		assert.Equal(t, int32(4), calls.Load())
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		var calls atomic.Int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer ts.Close()
+
+		c := New(ts.URL, &http.Client{}, WithMiddleware(Retry(policy)))
+
+		_, err := c.Get(context.Background(), "test")
+		assert.ErrorContains(t, err, "404")
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		slow := RetryPolicy{MaxRetries: 10, BaseDelay: time.Hour, MaxDelay: time.Hour}
+		c := New(ts.URL, &http.Client{}, WithMiddleware(Retry(slow)))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := c.Get(ctx, "test")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
+
+func TestRetry_Chain(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	// Middleware placed after Retry, such as RateLimit, sees every attempt.
+	var attempts atomic.Int32
+	count := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			attempts.Add(1)
+			return next.RoundTrip(r)
+		})
+	}
+
+	policy := RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond}
+	c := New(ts.URL, ts.Client(), WithMiddleware(Retry(policy), count))
+
+	resp, err := c.Get(context.Background(), "item.json")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(resp))
+	assert.Equal(t, int32(3), attempts.Load())
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+
+	for attempt := 0; attempt < 100; attempt++ {
+		d := p.delay(attempt, 0)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.LessOrEqual(t, d, 50*time.Millisecond)
+	}
+
+	assert.LessOrEqual(t, p.delay(0, 0), 10*time.Millisecond)
+	assert.Equal(t, 20*time.Millisecond, p.delay(0, 20*time.Millisecond))
+	assert.Equal(t, 50*time.Millisecond, p.delay(0, time.Minute))
+	assert.Equal(t, time.Duration(0), RetryPolicy{}.delay(3, 0))
+}
+
+func TestRetryable(t *testing.T) {
+	ctx := context.Background()
+
+	assert.True(t, retryable(ctx, &http.Response{StatusCode: http.StatusTooManyRequests}, nil))
+	assert.True(t, retryable(ctx, &http.Response{StatusCode: http.StatusBadGateway}, nil))
+	assert.False(t, retryable(ctx, &http.Response{StatusCode: http.StatusNotFound}, nil))
+	assert.False(t, retryable(ctx, &http.Response{StatusCode: http.StatusOK}, nil))
+	assert.True(t, retryable(ctx, nil, io.ErrUnexpectedEOF))
+	assert.True(t, retryable(ctx, nil, syscall.ECONNRESET))
+	assert.False(t, retryable(ctx, nil, errors.New("other")))
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.False(t, retryable(canceled, &http.Response{StatusCode: http.StatusBadGateway}, nil))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Duration(0), parseRetryAfter(http.Header{}, now))
+	assert.Equal(t, 3*time.Second, parseRetryAfter(http.Header{"Retry-After": {"3"}}, now))
+	assert.Equal(t, 90*time.Second, parseRetryAfter(
+		http.Header{"Retry-After": {now.Add(90 * time.Second).Format(http.TimeFormat)}}, now,
+	))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(
+		http.Header{"Retry-After": {now.Add(-time.Minute).Format(http.TimeFormat)}}, now,
+	))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(http.Header{"Retry-After": {"soon"}}, now))
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// EventPut replaces the data at Path.
+	EventPut = "put"
+	// EventPatch updates the children of the data at Path.
+	EventPatch = "patch"
+
+	eventKeepAlive   = "keep-alive"
+	eventCancel      = "cancel"
+	eventAuthRevoked = "auth_revoked"
+
+	// maxEventSize is the largest event the stream accepts.
+	maxEventSize = 4 << 20
+
+	// eventStream is the media type of server-sent events streams.
+	eventStream = "text/event-stream"
+)
+
+// ErrStreamCanceled is returned when the server cancels the stream.
+var ErrStreamCanceled = errors.New("stream canceled by the server")
+
+// Event is a change sent by the server over a server-sent events stream.
+type Event struct {
+	Type string
+	Path string
+	Data json.RawMessage
+}
+
+// Decode unmarshals the data of the event into v.
+func (e Event) Decode(v any) error {
+	return json.Unmarshal(e.Data, v)
+}
+
+// Stream makes a GET request to the API with the given suffix, asking for a server-sent events stream,
+// and calls handle for every put and patch event until the stream ends, the context is done,
+// or handle returns an error.
+// The timeout of the underlying HTTP client is ignored, since the stream is meant to stay open.
+func (c *Client) Stream(ctx context.Context, suffix string, handle func(Event) error) error {
+	uri := fmt.Sprintf("%s/%s", c.baseURL, suffix)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", eventStream)
+
+	hc := *c.c
+	hc.Timeout = 0
+
+	res, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return newStatusError(res)
+	}
+
+	scanner := bufio.NewScanner(res.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventSize)
+
+	var eventType, data string
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case line == "":
+			if err = dispatch(eventType, data, handle); err != nil {
+				return err
+			}
+
+			eventType, data = "", ""
+		case strings.HasPrefix(line, "event:"):
+			eventType = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data += strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+// dispatch decodes a complete event and hands put and patch events to handle.
+func dispatch(eventType, data string, handle func(Event) error) error {
+	switch eventType {
+	case EventPut, EventPatch:
+		payload := struct {
+			Path string          `json:"path"`
+			Data json.RawMessage `json:"data"`
+		}{}
+
+		if err := json.Unmarshal([]byte(data), &payload); err != nil {
+			return err
+		}
+
+		return handle(Event{Type: eventType, Path: payload.Path, Data: payload.Data})
+	case eventCancel, eventAuthRevoked:
+		return ErrStreamCanceled
+	case eventKeepAlive:
+		return nil
+	default:
+		// Unknown events carry no changes we know of.
+		return nil
+	}
+}
+
+// isStream reports whether the given request asks for a server-sent events stream.
+func isStream(r *http.Request) bool {
+	return r.Header.Get("Accept") == eventStream
+}
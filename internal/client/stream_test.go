@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Stream(t *testing.T) {
+	t.Run("put and patch events", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "text/event-stream", r.Header.Get("Accept"))
+			assert.Equal(t, "/topstories.json", r.URL.Path)
+
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "event: put\ndata: {\"path\":\"/\",\"data\":[1,2,3]}\n\n")
+			fmt.Fprint(w, "event: keep-alive\ndata: null\n\n")
+			fmt.Fprint(w, "event: patch\ndata: {\"path\":\"/\",\"data\":{\"0\":4}}\n\n")
+		}))
+		defer ts.Close()
+
+		c := New(ts.URL, &http.Client{})
+
+		var events []Event
+		err := c.Stream(context.Background(), "topstories.json", func(e Event) error {
+			events = append(events, e)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+
+		assert.Equal(t, EventPut, events[0].Type)
+		assert.Equal(t, "/", events[0].Path)
+
+		var ids []int
+		require.NoError(t, events[0].Decode(&ids))
+		assert.Equal(t, []int{1, 2, 3}, ids)
+
+		assert.Equal(t, EventPatch, events[1].Type)
+
+		patch := map[string]int{}
+		require.NoError(t, events[1].Decode(&patch))
+		assert.Equal(t, map[string]int{"0": 4}, patch)
+	})
+
+	t.Run("canceled by the server", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "event: cancel\ndata: null\n\n")
+		}))
+		defer ts.Close()
+
+		c := New(ts.URL, &http.Client{})
+
+		err := c.Stream(context.Background(), "topstories.json", func(e Event) error { return nil })
+		assert.ErrorIs(t, err, ErrStreamCanceled)
+	})
+
+	t.Run("handler error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "event: put\ndata: {\"path\":\"/\",\"data\":1}\n\n")
+			fmt.Fprint(w, "event: put\ndata: {\"path\":\"/\",\"data\":2}\n\n")
+		}))
+		defer ts.Close()
+
+		c := New(ts.URL, &http.Client{})
+		handlerErr := errors.New("handler error")
+
+		calls := 0
+		err := c.Stream(context.Background(), "topstories.json", func(e Event) error {
+			calls++
+			return handlerErr
+		})
+		assert.ErrorIs(t, err, handlerErr)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("invalid event data", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "event: put\ndata: {invalid\n\n")
+		}))
+		defer ts.Close()
+
+		c := New(ts.URL, &http.Client{})
+
+		err := c.Stream(context.Background(), "topstories.json", func(e Event) error { return nil })
+		assert.Error(t, err)
+	})
+
+	t.Run("unsuccessful response", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer ts.Close()
+
+		c := New(ts.URL, &http.Client{})
+
+		err := c.Stream(context.Background(), "topstories.json", func(e Event) error { return nil })
+		assert.ErrorContains(t, err, "401")
+
+		var se *StatusError
+		require.ErrorAs(t, err, &se)
+		assert.Equal(t, http.StatusUnauthorized, se.Code)
+		assert.Equal(t, ts.URL+"/topstories.json", se.URL)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "event: put\ndata: {\"path\":\"/\",\"data\":1}\n\n")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}))
+		defer ts.Close()
+
+		c := New(ts.URL, &http.Client{})
+		ctx, cancel := context.WithCancel(context.Background())
+
+		err := c.Stream(ctx, "topstories.json", func(e Event) error {
+			cancel()
+			return nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	// idleConnTimeout is how long an idle connection is kept in the pool before being closed.
+	idleConnTimeout = 90 * time.Second
+	// responseHeaderTimeout bounds the wait for the headers of a response, so that a stalled request
+	// gives up even if the timeout middleware is left out of the chain.
+	responseHeaderTimeout = 30 * time.Second
+	// apiHosts is the number of hosts the application talks to (the Firebase and the Algolia APIs).
+	apiHosts = 2
+)
+
+// NewTransport returns an HTTP transport that keeps connections alive and negotiates HTTP/2,
+// sized so that each of the given number of workers can hold an idle connection per host.
+func NewTransport(workers int) *http.Transport {
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          workers * apiHosts,
+		MaxIdleConnsPerHost:   workers,
+		IdleConnTimeout:       idleConnTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: responseHeaderTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTransport(t *testing.T) {
+	t.Run("sizes the pool from the workers", func(t *testing.T) {
+		tr := NewTransport(10)
+
+		assert.True(t, tr.ForceAttemptHTTP2)
+		assert.False(t, tr.DisableKeepAlives)
+		assert.Equal(t, 10, tr.MaxIdleConnsPerHost)
+		assert.Equal(t, 20, tr.MaxIdleConns)
+		assert.Equal(t, idleConnTimeout, tr.IdleConnTimeout)
+		assert.Equal(t, responseHeaderTimeout, tr.ResponseHeaderTimeout)
+	})
+
+	t.Run("keeps at least one connection", func(t *testing.T) {
+		tr := NewTransport(0)
+
+		assert.Equal(t, 1, tr.MaxIdleConnsPerHost)
+	})
+
+	t.Run("negotiates HTTP/2 and reuses connections", func(t *testing.T) {
+		ts, conns := newTLSServer(t)
+
+		c := New(ts.URL, &http.Client{Transport: tlsTransport(ts, NewTransport(4))})
+
+		for i := 0; i < 10; i++ {
+			resp, err := c.Get(context.Background(), "/item.json")
+			require.NoError(t, err)
+			assert.Equal(t, "HTTP/2.0", string(resp))
+		}
+
+		assert.Equal(t, 1, conns())
+	})
+}
+
+// BenchmarkClient_Get loads a page of items with a pool of workers, as the feeds do,
+// either reusing connections or opening a new one for every request.
+func BenchmarkClient_Get(b *testing.B) {
+	const (
+		workers = 10
+		perPage = 30
+	)
+
+	ts, _ := newTLSServer(b)
+
+	closing := NewTransport(workers)
+	closing.DisableKeepAlives = true
+
+	transports := map[string]*http.Transport{
+		"keep-alive": NewTransport(workers),
+		"close":      closing,
+	}
+
+	for name, tr := range transports {
+		b.Run(name, func(b *testing.B) {
+			c := New(ts.URL, &http.Client{Transport: tlsTransport(ts, tr)})
+			defer tr.CloseIdleConnections()
+
+			for i := 0; i < b.N; i++ {
+				loadPage(b, c, workers, perPage)
+			}
+		})
+	}
+}
+
+// loadPage fetches perPage items using the given number of workers.
+func loadPage(tb testing.TB, c *Client, workers, perPage int) {
+	ids := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range ids {
+				if _, err := c.Get(context.Background(), fmt.Sprintf("/item/%d.json", id)); err != nil {
+					tb.Error(err)
+				}
+			}
+		}()
+	}
+
+	for id := 0; id < perPage; id++ {
+		ids <- id
+	}
+	close(ids)
+	wg.Wait()
+}
+
+// newTLSServer starts an HTTP/2 TLS server answering with the protocol of each request,
+// and returns a function reporting how many connections it has accepted.
+func newTLSServer(tb testing.TB) (*httptest.Server, func() int) {
+	var (
+		mu    sync.Mutex
+		conns int
+	)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	}))
+	ts.EnableHTTP2 = true
+	ts.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			conns++
+			mu.Unlock()
+		}
+	}
+	ts.StartTLS()
+	tb.Cleanup(ts.Close)
+
+	return ts, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return conns
+	}
+}
+
+// tlsTransport makes the given transport trust the certificate of the test server.
+func tlsTransport(ts *httptest.Server, tr *http.Transport) *http.Transport {
+	tr.TLSClientConfig = &tls.Config{RootCAs: ts.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs}
+
+	return tr
+}
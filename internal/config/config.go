@@ -1,10 +1,17 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/adrg/xdg"
 	"gopkg.in/yaml.v3"
+
+	"github.com/KarolosLykos/hackertea/internal/constants"
 )
 
 const (
@@ -12,8 +19,115 @@ const (
 )
 
 type Config struct {
-	Style   Style `yaml:"style"`
-	Workers int   `yaml:"workers"`
+	Style     Style     `yaml:"style"`
+	Workers   int       `yaml:"workers"`
+	Tabs      []Feed    `yaml:"tabs"`
+	Retry     Retry     `yaml:"retry"`
+	RateLimit RateLimit `yaml:"rateLimit"`
+	HTTP      HTTP      `yaml:"http"`
+	Cache     Cache     `yaml:"cache"`
+}
+
+// Cache configures where fetched items are kept.
+// Type is either "lru", which holds up to MaxEntries items and about MaxBytes bytes,
+// evicting the least recently used ones, or "memory", which is unbounded.
+// A limit of zero is not enforced. If Disk is set, items are also kept on disk between sessions,
+// for up to DiskMaxAge since they were last written and up to about DiskMaxBytes bytes in all.
+type Cache struct {
+	Type         string        `yaml:"type"`
+	MaxEntries   int           `yaml:"maxEntries"`
+	MaxBytes     int64         `yaml:"maxBytes"`
+	Disk         bool          `yaml:"disk"`
+	DiskMaxAge   time.Duration `yaml:"diskMaxAge"`
+	DiskMaxBytes int64         `yaml:"diskMaxBytes"`
+}
+
+// HTTP configures the client used to reach the APIs.
+// Requests are logged to LogFile if it is set, and FaultRate, between 0 and 1,
+// is the fraction of requests failed on purpose to test how the application copes with errors.
+// Middleware lists the steps every request goes through, in order; the ones left out are skipped,
+// and the ones whose settings disable them are too.
+type HTTP struct {
+	Timeout    time.Duration `yaml:"timeout"`
+	UserAgent  string        `yaml:"userAgent"`
+	LogFile    string        `yaml:"logFile,omitempty"`
+	FaultRate  float64       `yaml:"faultRate,omitempty"`
+	Middleware []string      `yaml:"middleware"`
+}
+
+// Middleware that can be listed in the HTTP settings.
+const (
+	MiddlewareRetry          = "retry"
+	MiddlewareRateLimit      = "rateLimit"
+	MiddlewareTimeout        = "timeout"
+	MiddlewareMetrics        = "metrics"
+	MiddlewareUserAgent      = "userAgent"
+	MiddlewareLogging        = "logging"
+	MiddlewareFaultInjection = "faultInjection"
+)
+
+// validate checks that every listed middleware is known and listed once.
+func (h HTTP) validate() error {
+	seen := make(map[string]bool, len(h.Middleware))
+	for _, name := range h.Middleware {
+		if !slices.Contains(defaultMiddleware(), name) {
+			return fmt.Errorf("unknown middleware %q, expected one of %s", name, strings.Join(defaultMiddleware(), ", "))
+		}
+
+		if seen[name] {
+			return fmt.Errorf("middleware %q is listed twice", name)
+		}
+
+		seen[name] = true
+	}
+
+	return nil
+}
+
+// RateLimit configures how many requests per second are sent to each API.
+// A rate of zero disables the limit.
+type RateLimit struct {
+	RPS   float64 `yaml:"rps"`
+	Burst int     `yaml:"burst"`
+}
+
+// Retry configures how failed requests to the API are retried.
+type Retry struct {
+	Max       int           `yaml:"max"`
+	BaseDelay time.Duration `yaml:"baseDelay"`
+	MaxDelay  time.Duration `yaml:"maxDelay"`
+}
+
+// Feed declares a tab and the source of its stories.
+// Source is either one of the built-in feeds (top, new, best, ask, show, job)
+// or "user", in which case the tab lists the submissions of User.
+// If Filter is set, only stories whose title contains it are shown.
+type Feed struct {
+	Name   string `yaml:"name"`
+	Source string `yaml:"source"`
+	User   string `yaml:"user,omitempty"`
+	Filter string `yaml:"filter,omitempty"`
+}
+
+// validate checks that the tab has a name and a known source, and that user tabs name a user.
+func (f Feed) validate() error {
+	if f.Name == "" {
+		return errors.New("missing name")
+	}
+
+	switch constants.ItemType(f.Source) {
+	case constants.Items.TopItems, constants.Items.NewItems, constants.Items.BestItems,
+		constants.Items.AskItems, constants.Items.ShowItems, constants.Items.JobItems:
+		return nil
+	case constants.Items.User:
+		if f.User == "" {
+			return fmt.Errorf("source %q requires a user", f.Source)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unknown source %q, expected one of top, new, best, ask, show, job or user", f.Source)
+	}
 }
 
 type Style struct {
@@ -67,7 +181,7 @@ func LoadConfig() (*Config, error) {
 }
 
 // getConfig reads a configuration file from a specified path and decodes
-// it into a Config.
+// it into a Config. It returns an error naming the first invalid tab or middleware, if any.
 func getConfig(configPath string) (*Config, error) {
 	cFile, err := os.Open(configPath)
 	if err != nil {
@@ -76,11 +190,31 @@ func getConfig(configPath string) (*Config, error) {
 
 	defer cFile.Close()
 
-	cfg := &Config{}
+	// Settings missing from older configuration files keep their defaults.
+	cfg := &Config{
+		Retry:     defaultRetry(),
+		RateLimit: defaultRateLimit(),
+		HTTP:      defaultHTTP(),
+		Cache:     defaultCache(),
+	}
 	if err = yaml.NewDecoder(cFile).Decode(cfg); err != nil {
 		return nil, err
 	}
 
+	if len(cfg.Tabs) == 0 {
+		cfg.Tabs = defaultTabs()
+	}
+
+	for i, tab := range cfg.Tabs {
+		if err = tab.validate(); err != nil {
+			return nil, fmt.Errorf("tab %d %q: %w", i+1, tab.Name, err)
+		}
+	}
+
+	if err = cfg.HTTP.validate(); err != nil {
+		return nil, fmt.Errorf("http: %w", err)
+	}
+
 	return cfg, nil
 }
 
@@ -164,6 +298,76 @@ func basicConfig() *Config {
 				},
 			},
 		},
-		Workers: 10,
+		Workers:   10,
+		Tabs:      defaultTabs(),
+		Retry:     defaultRetry(),
+		RateLimit: defaultRateLimit(),
+		HTTP:      defaultHTTP(),
+		Cache:     defaultCache(),
+	}
+}
+
+// defaultCache returns the default cache settings.
+func defaultCache() Cache {
+	return Cache{
+		Type:         "lru",
+		MaxEntries:   10000,
+		MaxBytes:     64 << 20,
+		Disk:         true,
+		DiskMaxAge:   30 * 24 * time.Hour,
+		DiskMaxBytes: 256 << 20,
+	}
+}
+
+// defaultHTTP returns the default HTTP client settings.
+func defaultHTTP() HTTP {
+	return HTTP{
+		Timeout:    10 * time.Second,
+		UserAgent:  "hackertea",
+		Middleware: defaultMiddleware(),
+	}
+}
+
+// defaultMiddleware returns every middleware, in the order requests go through them by default:
+// each attempt of a request waits for the rate limiter and has its own timeout.
+func defaultMiddleware() []string {
+	return []string{
+		MiddlewareRetry,
+		MiddlewareRateLimit,
+		MiddlewareTimeout,
+		MiddlewareMetrics,
+		MiddlewareUserAgent,
+		MiddlewareLogging,
+		MiddlewareFaultInjection,
+	}
+}
+
+// defaultRateLimit returns the default rate limit settings.
+func defaultRateLimit() RateLimit {
+	return RateLimit{
+		RPS:   20,
+		Burst: 10,
+	}
+}
+
+// defaultRetry returns the default retry settings.
+func defaultRetry() Retry {
+	return Retry{
+		Max:       3,
+		BaseDelay: 250 * time.Millisecond,
+		MaxDelay:  5 * time.Second,
+	}
+}
+
+// defaultTabs returns the built-in feeds, in the order they are shown
+// when the configuration does not declare any tabs.
+func defaultTabs() []Feed {
+	return []Feed{
+		{Name: constants.TabTop, Source: string(constants.Items.TopItems)},
+		{Name: constants.TabNew, Source: string(constants.Items.NewItems)},
+		{Name: constants.TabBest, Source: string(constants.Items.BestItems)},
+		{Name: constants.TabAsk, Source: string(constants.Items.AskItems)},
+		{Name: constants.TabShow, Source: string(constants.Items.ShowItems)},
+		{Name: constants.TabJobs, Source: string(constants.Items.JobItems)},
 	}
 }
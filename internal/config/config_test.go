@@ -0,0 +1,117 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetConfig(t *testing.T) {
+	t.Run("declared tabs", func(t *testing.T) {
+		path := writeConfig(t, `
+workers: 5
+tabs:
+  - name: Rust
+    source: new
+    filter: rust
+  - name: pg
+    source: user
+    user: pg
+retry:
+  max: 5
+  baseDelay: 100ms
+rateLimit:
+  rps: 5
+http:
+  logFile: /tmp/hackertea.log
+cache:
+  type: memory
+  disk: false
+`)
+
+		cfg, err := getConfig(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, 5, cfg.Workers)
+		assert.Equal(t, []Feed{
+			{Name: "Rust", Source: "new", Filter: "rust"},
+			{Name: "pg", Source: "user", User: "pg"},
+		}, cfg.Tabs)
+		assert.Equal(t, Retry{Max: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 5 * time.Second}, cfg.Retry)
+		assert.Equal(t, RateLimit{RPS: 5, Burst: 10}, cfg.RateLimit)
+		assert.Equal(t, HTTP{
+			Timeout:    10 * time.Second,
+			UserAgent:  "hackertea",
+			LogFile:    "/tmp/hackertea.log",
+			Middleware: defaultMiddleware(),
+		}, cfg.HTTP)
+		assert.Equal(t, Cache{
+			Type:         "memory",
+			MaxEntries:   10000,
+			MaxBytes:     64 << 20,
+			DiskMaxAge:   30 * 24 * time.Hour,
+			DiskMaxBytes: 256 << 20,
+		}, cfg.Cache)
+	})
+
+	t.Run("default tabs", func(t *testing.T) {
+		path := writeConfig(t, "workers: 5\n")
+
+		cfg, err := getConfig(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, defaultTabs(), cfg.Tabs)
+		assert.Equal(t, defaultRetry(), cfg.Retry)
+		assert.Equal(t, defaultRateLimit(), cfg.RateLimit)
+		assert.Equal(t, defaultHTTP(), cfg.HTTP)
+		assert.Equal(t, defaultCache(), cfg.Cache)
+	})
+
+	t.Run("invalid tabs", func(t *testing.T) {
+		for content, expected := range map[string]string{
+			"tabs:\n  - name: Top\n    source: top\n  - name: Foo\n    source: foo\n": `tab 2 "Foo": unknown source "foo"`,
+			"tabs:\n  - name: pg\n    source: user\n":                                 `tab 1 "pg": source "user" requires a user`,
+			"tabs:\n  - source: top\n":                                                `tab 1 "": missing name`,
+		} {
+			_, err := getConfig(writeConfig(t, content))
+			assert.ErrorContains(t, err, expected)
+		}
+	})
+
+	t.Run("middleware", func(t *testing.T) {
+		path := writeConfig(t, "http:\n  middleware: [metrics, retry, rateLimit]\n")
+
+		cfg, err := getConfig(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{MiddlewareMetrics, MiddlewareRetry, MiddlewareRateLimit}, cfg.HTTP.Middleware)
+	})
+
+	t.Run("invalid middleware", func(t *testing.T) {
+		for content, expected := range map[string]string{
+			"http:\n  middleware: [retry, cache]\n": `http: unknown middleware "cache"`,
+			"http:\n  middleware: [retry, retry]\n": `http: middleware "retry" is listed twice`,
+		} {
+			_, err := getConfig(writeConfig(t, content))
+			assert.ErrorContains(t, err, expected)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := getConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.Error(t, err)
+	})
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
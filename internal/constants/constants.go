@@ -7,6 +7,9 @@ import (
 
 var CurrentTime = time.Now()
 
+// RefreshInterval is how often the API is polled for changed items.
+const RefreshInterval = 30 * time.Second
+
 type ItemType string
 
 func (i ItemType) Title() string {
@@ -15,19 +18,31 @@ func (i ItemType) Title() string {
 
 const (
 	BaseURL      = "https://hacker-news.firebaseio.com/v0"
+	SearchURL    = "https://hn.algolia.com/api/v1"
 	NewSuffix    = "newstories.json"
 	TopSuffix    = "topstories.json"
 	BestSuffix   = "beststories.json"
 	AskSuffix    = "askstories.json"
+	ShowSuffix   = "showstories.json"
+	JobSuffix    = "jobstories.json"
 	SingleSuffix = "item/%s.json"
+	UserSuffix   = "user/%s.json"
+	UpdateSuffix = "updates.json"
+	MaxSuffix    = "maxitem.json"
+
+	SearchSuffix       = "search"
+	SearchByDateSuffix = "search_by_date"
 
-	TabTop  = "Top"
-	TabNew  = "New"
-	TabBest = "Best"
-	TabAsk  = "Ask"
-	Linux   = "linux"
-	Windows = "windows"
-	Darwin  = "darwin"
+	TabTop    = "Top"
+	TabNew    = "New"
+	TabBest   = "Best"
+	TabAsk    = "Ask"
+	TabShow   = "Show"
+	TabJobs   = "Jobs"
+	TabSearch = "Search"
+	Linux     = "linux"
+	Windows   = "windows"
+	Darwin    = "darwin"
 )
 
 var Items = struct {
@@ -35,13 +50,19 @@ var Items = struct {
 	TopItems   ItemType
 	BestItems  ItemType
 	AskItems   ItemType
+	ShowItems  ItemType
+	JobItems   ItemType
 	SingleItem ItemType
+	User       ItemType
 }{
 	NewItems:   "new",
 	TopItems:   "top",
 	BestItems:  "best",
 	AskItems:   "ask",
+	ShowItems:  "show",
+	JobItems:   "job",
 	SingleItem: "item",
+	User:       "user",
 }
 
 const (
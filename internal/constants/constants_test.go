@@ -19,6 +19,14 @@ func TestItemType_Title(t *testing.T) {
 		t.Errorf("wanted New got %v", items.NewItems.Title())
 	}
 
+	if items.ShowItems.Title() != "SHOW" {
+		t.Errorf("wanted Show got %v", items.ShowItems.Title())
+	}
+
+	if items.JobItems.Title() != "JOB" {
+		t.Errorf("wanted Job got %v", items.JobItems.Title())
+	}
+
 	if items.SingleItem.Title() != "ITEM" {
 		t.Errorf("wanted Item got %v", items.SingleItem.Title())
 	}
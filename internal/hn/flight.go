@@ -0,0 +1,80 @@
+package hn
+
+import (
+	"context"
+	"sync"
+
+	"github.com/KarolosLykos/hackertea/internal/item"
+)
+
+// flight is an item being fetched, shared by every caller asking for it in the meantime.
+type flight struct {
+	done    chan struct{}
+	item    *item.Item
+	err     error
+	callers int
+	cancel  context.CancelFunc
+}
+
+// flights coalesces concurrent fetches of the same item, so that they send a single request.
+type flights struct {
+	mu sync.Mutex
+	m  map[int]*flight
+}
+
+// do calls fetch for the given item ID, unless a fetch of the same item is already in flight,
+// in which case it waits for that one and returns its result.
+// A caller whose context is done stops waiting right away; the fetch itself is canceled
+// once all of its callers have given up.
+func (f *flights) do(ctx context.Context, id int, fetch func(ctx context.Context) (*item.Item, error)) (*item.Item, error) {
+	f.mu.Lock()
+	if f.m == nil {
+		f.m = make(map[int]*flight)
+	}
+
+	fl, ok := f.m[id]
+	if !ok {
+		// The fetch outlives the caller that started it if others are waiting for it.
+		fctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		fl = &flight{done: make(chan struct{}), cancel: cancel}
+		f.m[id] = fl
+
+		go func() {
+			fl.item, fl.err = fetch(fctx)
+			cancel()
+			f.forget(id, fl)
+			close(fl.done)
+		}()
+	}
+
+	fl.callers++
+	f.mu.Unlock()
+
+	select {
+	case <-fl.done:
+		return fl.item, fl.err
+	case <-ctx.Done():
+		f.mu.Lock()
+		fl.callers--
+		if fl.callers == 0 {
+			fl.cancel()
+			// Later callers must not join a canceled fetch.
+			if f.m[id] == fl {
+				delete(f.m, id)
+			}
+		}
+		f.mu.Unlock()
+
+		return nil, ctx.Err()
+	}
+}
+
+// forget removes the given flight, unless it was already replaced by a newer one.
+func (f *flights) forget(id int, fl *flight) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.m[id] == fl {
+		delete(f.m, id)
+	}
+}
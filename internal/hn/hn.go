@@ -1,40 +1,147 @@
 package hn
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/KarolosLykos/hackertea/internal/cache"
 	"github.com/KarolosLykos/hackertea/internal/client"
 	"github.com/KarolosLykos/hackertea/internal/constants"
 	"github.com/KarolosLykos/hackertea/internal/item"
+	"github.com/KarolosLykos/hackertea/internal/user"
 )
 
-var ErrInvalidItemType = errors.New("invalid item type")
+var (
+	ErrInvalidItemType = errors.New("invalid item type")
+	// ErrNotFound is returned when the API knows no item or user with the requested ID.
+	ErrNotFound = errors.New("not found")
+	// ErrDeleted and ErrDead are returned by Removed for items taken down by their author or by moderators.
+	ErrDeleted = errors.New("deleted")
+	ErrDead    = errors.New("dead")
+)
+
+// null is what the API answers for an unknown ID.
+var null = []byte("null")
 
 type Service interface {
 	GetItems(ctx context.Context, item constants.ItemType) ([]int, error)
 	GetItem(ctx context.Context, id int) (*item.Item, error)
+	GetItemsByIDs(ctx context.Context, ids []int) ([]*item.Item, []error)
+	GetItemsByIDsFunc(ctx context.Context, ids []int, fn func(n int, it *item.Item, err error))
+	GetUser(ctx context.Context, id string) (*user.User, error)
+	GetUpdates(ctx context.Context) (*Updates, error)
+	GetMaxItem(ctx context.Context) (int, error)
+	StreamItems(ctx context.Context, item constants.ItemType, fn func([]int)) error
+}
+
+// Updates holds the items and profiles that changed recently.
+type Updates struct {
+	Items    []int    `json:"items"`
+	Profiles []string `json:"profiles"`
+}
+
+// Feed holds the story IDs of a feed, such as the top stories.
+type Feed struct {
+	Type constants.ItemType
+	IDs  []int
+}
+
+// Update is sent by Watch after every poll of the API.
+type Update struct {
+	Updates
+	// MaxItem is the largest item ID known to the API.
+	MaxItem int
+	// NewItems is the number of items created since the first poll.
+	NewItems int
 }
 
+const (
+	// defaultWorkers is the number of items fetched at the same time, unless set with WithWorkers.
+	defaultWorkers = 10
+	// revalidateTimeout bounds the background fetch of an expired item.
+	revalidateTimeout = 30 * time.Second
+	// revalidatedBuffer is the number of revalidated items kept until they are received.
+	revalidatedBuffer = 256
+	// revalidatedFeedsBuffer is the number of revalidated feeds kept until they are received.
+	revalidatedFeedsBuffer = 8
+)
+
 type HN struct {
-	c     client.HttpClient
-	cache cache.Cache
+	c                client.HttpClient
+	cache            cache.Cache
+	flights          flights
+	workers          int
+	feeds            cache.FeedCache
+	revalidating     sync.Map
+	revalidated      chan *item.Item
+	feedsRevalidated chan Feed
 }
 
-func New(c client.HttpClient, cache cache.Cache) *HN {
-	return &HN{c: c, cache: cache}
+// Option configures an HN client.
+type Option func(*HN)
+
+// WithWorkers sets how many items GetItemsByIDs fetches at the same time.
+func WithWorkers(workers int) Option {
+	return func(h *HN) {
+		h.workers = workers
+	}
+}
+
+// WithFeedCache sets the cache keeping the story IDs of the feeds, so that GetItems can answer
+// without waiting for the API. Feeds are not cached unless set.
+func WithFeedCache(feeds cache.FeedCache) Option {
+	return func(h *HN) {
+		h.feeds = feeds
+	}
+}
+
+func New(c client.HttpClient, cache cache.Cache, opts ...Option) *HN {
+	h := &HN{
+		c:                c,
+		cache:            cache,
+		workers:          defaultWorkers,
+		revalidated:      make(chan *item.Item, revalidatedBuffer),
+		feedsRevalidated: make(chan Feed, revalidatedFeedsBuffer),
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
 }
 
+// GetItems returns the story IDs of the given feed, from the feed cache if possible.
+// Expired feeds are returned right away and fetched again in the background; see RevalidatedFeeds.
 func (h *HN) GetItems(ctx context.Context, item constants.ItemType) ([]int, error) {
 	suffix, err := getSuffix(item)
 	if err != nil {
 		return nil, err
 	}
 
+	if h.feeds != nil {
+		if e, ok := h.feeds.GetFeed(string(item)); ok {
+			if e.Expired(time.Now()) {
+				h.revalidateFeed(ctx, item)
+			}
+
+			return e.IDs, nil
+		}
+	}
+
+	return h.fetchFeed(ctx, item, suffix)
+}
+
+// fetchFeed gets the story IDs of the given feed from the API and caches them.
+func (h *HN) fetchFeed(ctx context.Context, item constants.ItemType, suffix string) ([]int, error) {
 	resp, err := h.c.Get(ctx, suffix)
 	if err != nil {
 		return nil, err
@@ -45,15 +152,112 @@ func (h *HN) GetItems(ctx context.Context, item constants.ItemType) ([]int, erro
 		return nil, err
 	}
 
+	if h.feeds != nil {
+		h.feeds.SetFeed(string(item), cache.FeedEntry{IDs: items, StoredAt: time.Now()})
+	}
+
 	return items, nil
 }
 
+// RevalidatedFeeds returns the channel receiving the expired feeds served by GetItems, once fetched again.
+// Feeds are dropped rather than waiting for the channel to be drained.
+func (h *HN) RevalidatedFeeds() <-chan Feed {
+	return h.feedsRevalidated
+}
+
+// revalidateFeed fetches the given feed again in the background,
+// unless it is already being revalidated, and sends it to the RevalidatedFeeds channel.
+func (h *HN) revalidateFeed(ctx context.Context, item constants.ItemType) {
+	if _, busy := h.revalidating.LoadOrStore(item, struct{}{}); busy {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), revalidateTimeout)
+
+	go func() {
+		defer cancel()
+		defer h.revalidating.Delete(item)
+
+		suffix, _ := getSuffix(item)
+
+		ids, err := h.fetchFeed(ctx, item, suffix)
+		if err != nil {
+			return
+		}
+
+		select {
+		case h.feedsRevalidated <- Feed{Type: item, IDs: ids}:
+		default:
+		}
+	}()
+}
+
+// GetItem returns the item with the given ID, from the cache if possible.
+// Concurrent calls for the same item share a single request.
+// It returns ErrNotFound if there is no such item. Deleted and dead items are returned
+// like any other, since their replies are still part of the discussion; see Removed.
+// Expired items are returned right away and fetched again in the background; see Revalidated.
 func (h *HN) GetItem(ctx context.Context, id int) (*item.Item, error) {
-	v, ok := h.cache.Get(id)
+	e, ok := h.cache.Get(id)
 	if ok {
-		return v, nil
+		if e.Expired(time.Now()) {
+			h.revalidate(ctx, id)
+		}
+
+		return e.Item, nil
+	}
+
+	return h.flights.do(ctx, id, func(ctx context.Context) (*item.Item, error) {
+		return h.fetchItem(ctx, id)
+	})
+}
+
+// CacheStats returns the usage statistics of the item cache, if it keeps any.
+func (h *HN) CacheStats() (cache.Stats, bool) {
+	r, ok := h.cache.(cache.Reporter)
+	if !ok {
+		return cache.Stats{}, false
 	}
 
+	return r.Stats(), true
+}
+
+// Revalidated returns the channel receiving the expired items served by GetItem, once fetched again.
+// Items are dropped rather than waiting for the channel to be drained.
+func (h *HN) Revalidated() <-chan *item.Item {
+	return h.revalidated
+}
+
+// revalidate fetches the item with the given ID again in the background,
+// unless it is already being revalidated, and sends it to the Revalidated channel.
+func (h *HN) revalidate(ctx context.Context, id int) {
+	if _, busy := h.revalidating.LoadOrStore(id, struct{}{}); busy {
+		return
+	}
+
+	// The refresh outlives the request that found the item expired.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), revalidateTimeout)
+
+	go func() {
+		defer cancel()
+		defer h.revalidating.Delete(id)
+
+		it, err := h.flights.do(ctx, id, func(ctx context.Context) (*item.Item, error) {
+			return h.fetchItem(ctx, id)
+		})
+		if err != nil {
+			return
+		}
+
+		select {
+		case h.revalidated <- it:
+		default:
+		}
+	}()
+}
+
+// fetchItem gets the item with the given ID from the API and caches it.
+func (h *HN) fetchItem(ctx context.Context, id int) (*item.Item, error) {
 	suffix, _ := getSuffix(constants.Items.SingleItem)
 
 	uri := fmt.Sprintf(suffix, strconv.Itoa(id))
@@ -63,16 +267,314 @@ func (h *HN) GetItem(ctx context.Context, id int) (*item.Item, error) {
 		return nil, err
 	}
 
+	if bytes.Equal(bytes.TrimSpace(resp), null) {
+		// Unknown IDs are not cached, as they may be allocated later on.
+		return nil, fmt.Errorf("item %d: %w", id, ErrNotFound)
+	}
+
 	i := &item.Item{}
 	if err = json.Unmarshal(resp, i); err != nil {
 		return nil, err
 	}
 
-	h.cache.Set(id, i)
+	h.cache.Set(id, cache.NewEntry(i, time.Now()))
 
 	return i, nil
 }
 
+// Removed returns ErrDeleted or ErrDead if the given item was taken down, and nil otherwise.
+func Removed(i *item.Item) error {
+	switch {
+	case i.Deleted:
+		return ErrDeleted
+	case i.Dead:
+		return ErrDead
+	default:
+		return nil
+	}
+}
+
+// GetItemsByIDs fetches the items with the given IDs concurrently, using a pool of workers.
+// The returned items and errors are in the same order as the IDs:
+// for every ID, either the item is set or the error tells why it could not be fetched.
+func (h *HN) GetItemsByIDs(ctx context.Context, ids []int) ([]*item.Item, []error) {
+	items := make([]*item.Item, len(ids))
+	errs := make([]error, len(ids))
+
+	h.GetItemsByIDsFunc(ctx, ids, func(n int, it *item.Item, err error) {
+		items[n], errs[n] = it, err
+	})
+
+	return items, errs
+}
+
+// GetItemsByIDsFunc fetches the items with the given IDs concurrently, using a pool of workers,
+// and calls fn with every item as soon as it arrives, along with its index in ids.
+// Either the item is set or the error tells why it could not be fetched.
+// fn is called from several goroutines at once; GetItemsByIDsFunc returns once every call is done.
+func (h *HN) GetItemsByIDsFunc(ctx context.Context, ids []int, fn func(n int, it *item.Item, err error)) {
+	if len(ids) == 0 {
+		return
+	}
+
+	workers := max(min(h.workers, len(ids)), 1)
+
+	work := make(chan int)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range work {
+				it, err := h.GetItem(ctx, ids[n])
+				fn(n, it, err)
+			}
+		}()
+	}
+
+	for n := range ids {
+		work <- n
+	}
+
+	close(work)
+	wg.Wait()
+}
+
+func (h *HN) GetUser(ctx context.Context, id string) (*user.User, error) {
+	suffix, _ := getSuffix(constants.Items.User)
+
+	uri := fmt.Sprintf(suffix, url.PathEscape(id))
+
+	resp, err := h.c.Get(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.Equal(bytes.TrimSpace(resp), null) {
+		return nil, fmt.Errorf("user %s: %w", id, ErrNotFound)
+	}
+
+	u := &user.User{}
+	if err = json.Unmarshal(resp, u); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+func (h *HN) GetUpdates(ctx context.Context) (*Updates, error) {
+	resp, err := h.c.Get(ctx, constants.UpdateSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &Updates{}
+	if err = json.Unmarshal(resp, u); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+func (h *HN) GetMaxItem(ctx context.Context) (int, error) {
+	resp, err := h.c.Get(ctx, constants.MaxSuffix)
+	if err != nil {
+		return 0, err
+	}
+
+	var maxItem int
+	if err = json.Unmarshal(resp, &maxItem); err != nil {
+		return 0, err
+	}
+
+	return maxItem, nil
+}
+
+// StreamItems streams the story IDs of the given feed, calling fn with the whole
+// list every time the feed changes, until ctx is done or the stream ends.
+// Entries set to null are deleted: they leave a hole in the ranks sent afterwards,
+// which is left out of the lists passed to fn. Every version of the feed is also kept in the feed cache, if any.
+func (h *HN) StreamItems(ctx context.Context, item constants.ItemType, fn func([]int)) error {
+	suffix, err := getSuffix(item)
+	if err != nil {
+		return err
+	}
+
+	// ranks holds the feed as the server sees it, with zero standing for a deleted entry.
+	var ranks []int
+
+	return h.c.Stream(ctx, suffix, func(e client.Event) error {
+		switch {
+		case e.Type == client.EventPut && e.Path == "/":
+			var list []*int
+			if err := e.Decode(&list); err != nil {
+				return err
+			}
+
+			ranks = ranks[:0]
+			for index, id := range list {
+				ranks = setID(ranks, index, id)
+			}
+		case e.Type == client.EventPut:
+			index, err := strconv.Atoi(strings.TrimPrefix(e.Path, "/"))
+			if err != nil {
+				return err
+			}
+
+			var id *int
+			if err = e.Decode(&id); err != nil {
+				return err
+			}
+
+			ranks = setID(ranks, index, id)
+		case e.Type == client.EventPatch && e.Path == "/":
+			changes := map[string]*int{}
+			if err := e.Decode(&changes); err != nil {
+				return err
+			}
+
+			for key, id := range changes {
+				index, err := strconv.Atoi(key)
+				if err != nil {
+					return err
+				}
+
+				ranks = setID(ranks, index, id)
+			}
+		default:
+			return nil
+		}
+
+		ids := make([]int, 0, len(ranks))
+		for _, id := range ranks {
+			if id != 0 {
+				ids = append(ids, id)
+			}
+		}
+
+		if h.feeds != nil {
+			h.feeds.SetFeed(string(item), cache.FeedEntry{IDs: append([]int(nil), ids...), StoredAt: time.Now()})
+		}
+
+		fn(ids)
+
+		return nil
+	})
+}
+
+// WatchFeeds streams the story IDs of the given feeds until ctx is done, sending a feed on the returned channel
+// every time it changes, so that the tabs can re-rank live. Streams that end or fail are opened again
+// after interval. The returned channel is closed when ctx is done.
+func (h *HN) WatchFeeds(ctx context.Context, interval time.Duration, items ...constants.ItemType) <-chan Feed {
+	feeds := make(chan Feed)
+
+	wg := sync.WaitGroup{}
+	for _, it := range items {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				_ = h.StreamItems(ctx, it, func(ids []int) {
+					select {
+					case feeds <- Feed{Type: it, IDs: ids}:
+					case <-ctx.Done():
+					}
+				})
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(interval):
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(feeds)
+	}()
+
+	return feeds
+}
+
+// setID sets the ID at the given rank, growing the list if needed.
+// A nil ID deletes the entry, and the deleted entries at the end of the list are dropped.
+func setID(ranks []int, index int, id *int) []int {
+	if id == nil || *id == 0 {
+		if index < len(ranks) {
+			ranks[index] = 0
+		}
+
+		for len(ranks) > 0 && ranks[len(ranks)-1] == 0 {
+			ranks = ranks[:len(ranks)-1]
+		}
+
+		return ranks
+	}
+
+	for len(ranks) <= index {
+		ranks = append(ranks, 0)
+	}
+
+	ranks[index] = *id
+
+	return ranks
+}
+
+// Watch polls the updates and maxitem endpoints every interval until ctx is done.
+// Items reported as changed are removed from the cache before the update is sent,
+// so the next GetItem call fetches them again. Failed polls are skipped.
+// The returned channel is closed when ctx is done.
+func (h *HN) Watch(ctx context.Context, interval time.Duration) <-chan Update {
+	updates := make(chan Update)
+
+	go func() {
+		defer close(updates)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		firstItem := 0
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			u, err := h.GetUpdates(ctx)
+			if err != nil {
+				continue
+			}
+
+			maxItem, err := h.GetMaxItem(ctx)
+			if err != nil {
+				continue
+			}
+
+			if firstItem == 0 {
+				firstItem = maxItem
+			}
+
+			for _, id := range u.Items {
+				h.cache.Delete(id)
+			}
+
+			select {
+			case updates <- Update{Updates: *u, MaxItem: maxItem, NewItems: maxItem - firstItem}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return updates
+}
+
 func getSuffix(item constants.ItemType) (string, error) {
 	switch item {
 	case constants.Items.NewItems:
@@ -83,8 +585,14 @@ func getSuffix(item constants.ItemType) (string, error) {
 		return constants.BestSuffix, nil
 	case constants.Items.AskItems:
 		return constants.AskSuffix, nil
+	case constants.Items.ShowItems:
+		return constants.ShowSuffix, nil
+	case constants.Items.JobItems:
+		return constants.JobSuffix, nil
 	case constants.Items.SingleItem:
 		return constants.SingleSuffix, nil
+	case constants.Items.User:
+		return constants.UserSuffix, nil
 	default:
 		return "", ErrInvalidItemType
 	}
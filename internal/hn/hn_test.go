@@ -3,16 +3,24 @@ package hn
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	itemcache "github.com/KarolosLykos/hackertea/internal/cache"
+	httpclient "github.com/KarolosLykos/hackertea/internal/client"
 	"github.com/KarolosLykos/hackertea/internal/constants"
 	"github.com/KarolosLykos/hackertea/internal/item"
 	"github.com/KarolosLykos/hackertea/internal/mock/cache"
 	"github.com/KarolosLykos/hackertea/internal/mock/client"
+	"github.com/KarolosLykos/hackertea/internal/user"
 )
 
 func TestGetSuffix(t *testing.T) {
@@ -26,7 +34,11 @@ func TestGetSuffix(t *testing.T) {
 		{name: "New items", value: constants.Items.NewItems, suffix: constants.NewSuffix},
 		{name: "Top items", value: constants.Items.TopItems, suffix: constants.TopSuffix},
 		{name: "Best items", value: constants.Items.BestItems, suffix: constants.BestSuffix},
+		{name: "Ask items", value: constants.Items.AskItems, suffix: constants.AskSuffix},
+		{name: "Show items", value: constants.Items.ShowItems, suffix: constants.ShowSuffix},
+		{name: "Job items", value: constants.Items.JobItems, suffix: constants.JobSuffix},
 		{name: "Single item", value: constants.Items.SingleItem, suffix: constants.SingleSuffix},
+		{name: "User", value: constants.Items.User, suffix: constants.UserSuffix},
 		{name: "Error", value: "", err: ErrInvalidItemType},
 	}
 
@@ -85,16 +97,59 @@ func TestGetItems(t *testing.T) {
 	}
 }
 
+func TestHN_GetItemsCached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock_client.NewMockHttpClient(ctrl)
+
+	feeds, err := itemcache.NewDisk(t.TempDir(), 0, 0)
+	require.NoError(t, err)
+	t.Cleanup(feeds.Close)
+
+	h := New(mockClient, nil, WithFeedCache(feeds))
+
+	// The feed is fetched once, then served from the cache while it is fresh.
+	mockClient.EXPECT().Get(gomock.Any(), constants.TopSuffix).Times(1).Return([]byte(`[1, 2, 3]`), nil)
+
+	for i := 0; i < 2; i++ {
+		ids, err := h.GetItems(context.Background(), constants.Items.TopItems)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, ids)
+	}
+
+	// An expired feed is served right away, and fetched again in the background.
+	feeds.SetFeed(string(constants.Items.TopItems), itemcache.FeedEntry{IDs: []int{1, 2, 3}, StoredAt: time.Now().Add(-time.Hour)})
+	mockClient.EXPECT().Get(gomock.Any(), constants.TopSuffix).Times(1).Return([]byte(`[4, 1, 2]`), nil)
+
+	ids, err := h.GetItems(context.Background(), constants.Items.TopItems)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, ids)
+
+	select {
+	case f := <-h.RevalidatedFeeds():
+		assert.Equal(t, Feed{Type: constants.Items.TopItems, IDs: []int{4, 1, 2}}, f)
+	case <-time.After(time.Second):
+		t.Fatal("the feed was not revalidated")
+	}
+
+	e, ok := feeds.GetFeed(string(constants.Items.TopItems))
+	require.True(t, ok)
+	assert.Equal(t, []int{4, 1, 2}, e.IDs)
+	assert.False(t, e.Expired(time.Now()))
+}
+
 func TestHN_GetItem(t *testing.T) {
 	testCases := []struct {
-		name       string
-		id         int
-		clientStub func(client *mock_client.MockHttpClient)
-		cacheStub  func(cache *mock_cache.MockCache)
-		response   []byte
-		respErr    error
-		expected   *item.Item
-		expectErr  bool
+		name        string
+		id          int
+		clientStub  func(client *mock_client.MockHttpClient)
+		cacheStub   func(cache *mock_cache.MockCache)
+		response    []byte
+		respErr     error
+		expected    *item.Item
+		expectErr   bool
+		expectedErr error
 	}{
 		{
 			name: "cache hit",
@@ -103,7 +158,7 @@ func TestHN_GetItem(t *testing.T) {
 				client.EXPECT().Get(gomock.Any(), gomock.Any()).Times(0)
 			},
 			cacheStub: func(cache *mock_cache.MockCache) {
-				cache.EXPECT().Get(gomock.Any()).Times(1).Return(&item.Item{ID: 123}, true)
+				cache.EXPECT().Get(gomock.Any()).Times(1).Return(itemcache.NewEntry(&item.Item{ID: 123}, time.Now()), true)
 			},
 			response:  nil,
 			expected:  &item.Item{ID: 123},
@@ -116,19 +171,48 @@ func TestHN_GetItem(t *testing.T) {
 				client.EXPECT().Get(gomock.Any(), gomock.Any()).Times(1).Return([]byte(`{"id":123}`), nil)
 			},
 			cacheStub: func(cache *mock_cache.MockCache) {
-				cache.EXPECT().Get(gomock.Any()).Times(1).Return(nil, false)
+				cache.EXPECT().Get(gomock.Any()).Times(1).Return(itemcache.Entry{}, false)
 				cache.EXPECT().Set(gomock.Any(), gomock.Any()).Times(1)
 			},
 			expected:  &item.Item{ID: 123},
 			expectErr: false,
 		},
+		{
+			name: "unknown item is not cached",
+			id:   123,
+			clientStub: func(client *mock_client.MockHttpClient) {
+				client.EXPECT().Get(gomock.Any(), gomock.Any()).Times(1).Return([]byte("null"), nil)
+			},
+			cacheStub: func(cache *mock_cache.MockCache) {
+				cache.EXPECT().Get(gomock.Any()).Times(1).Return(itemcache.Entry{}, false)
+				cache.EXPECT().Set(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expected:    nil,
+			expectErr:   true,
+			expectedErr: ErrNotFound,
+		},
+		{
+			name: "deleted item",
+			id:   123,
+			clientStub: func(client *mock_client.MockHttpClient) {
+				client.EXPECT().Get(gomock.Any(), gomock.Any()).Times(1).Return([]byte(`{"id":123,"deleted":true}`), nil)
+			},
+			cacheStub: func(cache *mock_cache.MockCache) {
+				cache.EXPECT().Get(gomock.Any()).Times(1).Return(itemcache.Entry{}, false)
+				cache.EXPECT().Set(gomock.Any(), gomock.Any()).Times(1)
+			},
+			expected:  &item.Item{ID: 123, Deleted: true},
+			expectErr: false,
+		},
 		{
 			name: "invalid response",
 			id:   123,
 			clientStub: func(client *mock_client.MockHttpClient) {
 				client.EXPECT().Get(gomock.Any(), gomock.Any()).Times(1).Return([]byte(`{"invalid"`), nil)
 			},
-			cacheStub: func(cache *mock_cache.MockCache) { cache.EXPECT().Get(gomock.Any()).Times(1).Return(nil, false) },
+			cacheStub: func(cache *mock_cache.MockCache) {
+				cache.EXPECT().Get(gomock.Any()).Times(1).Return(itemcache.Entry{}, false)
+			},
 			expected:  nil,
 			expectErr: true,
 		},
@@ -138,7 +222,9 @@ func TestHN_GetItem(t *testing.T) {
 			clientStub: func(client *mock_client.MockHttpClient) {
 				client.EXPECT().Get(gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("get error"))
 			},
-			cacheStub: func(cache *mock_cache.MockCache) { cache.EXPECT().Get(gomock.Any()).Times(1).Return(nil, false) },
+			cacheStub: func(cache *mock_cache.MockCache) {
+				cache.EXPECT().Get(gomock.Any()).Times(1).Return(itemcache.Entry{}, false)
+			},
 			expected:  nil,
 			expectErr: true,
 		},
@@ -164,6 +250,9 @@ func TestHN_GetItem(t *testing.T) {
 			// Check error
 			if tc.expectErr {
 				require.Error(t, err)
+				if tc.expectedErr != nil {
+					assert.ErrorIs(t, err, tc.expectedErr)
+				}
 				return
 			}
 			require.NoError(t, err)
@@ -173,3 +262,572 @@ func TestHN_GetItem(t *testing.T) {
 		})
 	}
 }
+
+func TestHN_GetItemExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock_client.NewMockHttpClient(ctrl)
+
+	release := make(chan struct{})
+	mockClient.EXPECT().Get(gomock.Any(), "item/123.json").Times(1).DoAndReturn(
+		func(context.Context, string) ([]byte, error) {
+			<-release
+			return []byte(`{"id":123,"score":2}`), nil
+		},
+	)
+
+	c := itemcache.New()
+	c.Set(123, itemcache.NewEntry(&item.Item{ID: 123, Score: 1}, time.Now().Add(-time.Hour)))
+
+	h := New(mockClient, c)
+
+	// The expired item is served right away, and fetched again only once.
+	for i := 0; i < 3; i++ {
+		it, err := h.GetItem(context.Background(), 123)
+		require.NoError(t, err)
+		assert.Equal(t, 1, it.Score)
+	}
+
+	close(release)
+
+	select {
+	case it := <-h.Revalidated():
+		assert.Equal(t, &item.Item{ID: 123, Score: 2}, it)
+	case <-time.After(time.Second):
+		t.Fatal("the item was not revalidated")
+	}
+
+	e, ok := c.Get(123)
+	require.True(t, ok)
+	assert.Equal(t, 2, e.Item.Score)
+	assert.False(t, e.Expired(time.Now()))
+}
+
+func TestRemoved(t *testing.T) {
+	assert.NoError(t, Removed(&item.Item{ID: 1}))
+	assert.ErrorIs(t, Removed(&item.Item{ID: 1, Deleted: true}), ErrDeleted)
+	assert.ErrorIs(t, Removed(&item.Item{ID: 1, Dead: true}), ErrDead)
+}
+
+func TestHN_GetItemsByIDs(t *testing.T) {
+	testCases := []struct {
+		name    string
+		workers int
+		ids     []int
+	}{
+		{name: "more items than workers", workers: 2, ids: []int{1, 2, 3, 4, 5}},
+		{name: "more workers than items", workers: 10, ids: []int{1, 2}},
+		{name: "no workers", workers: 0, ids: []int{1, 2}},
+		{name: "no items", workers: 2, ids: []int{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCache := mock_cache.NewMockCache(ctrl)
+			mockClient := mock_client.NewMockHttpClient(ctrl)
+
+			mockCache.EXPECT().Get(gomock.Any()).AnyTimes().Return(itemcache.Entry{}, false)
+			mockCache.EXPECT().Set(gomock.Any(), gomock.Any()).AnyTimes()
+			mockClient.EXPECT().Get(gomock.Any(), gomock.Any()).Times(len(tc.ids)).DoAndReturn(
+				func(_ context.Context, suffix string) ([]byte, error) {
+					var id int
+					_, _ = fmt.Sscanf(suffix, "item/%d.json", &id)
+					if id == 2 {
+						return nil, errors.New("error getting item")
+					}
+
+					return []byte(fmt.Sprintf(`{"id":%d}`, id)), nil
+				},
+			)
+
+			h := New(mockClient, mockCache, WithWorkers(tc.workers))
+
+			items, errs := h.GetItemsByIDs(context.Background(), tc.ids)
+
+			require.Len(t, items, len(tc.ids))
+			require.Len(t, errs, len(tc.ids))
+
+			for n, id := range tc.ids {
+				if id == 2 {
+					assert.Nil(t, items[n])
+					assert.EqualError(t, errs[n], "error getting item")
+					continue
+				}
+
+				assert.NoError(t, errs[n])
+				assert.Equal(t, &item.Item{ID: id}, items[n])
+			}
+		})
+	}
+}
+
+func TestHN_GetItemsByIDsFunc(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock_client.NewMockHttpClient(ctrl)
+
+	// The first item is the slowest, so the others must be delivered before it.
+	release := make(chan struct{})
+	mockClient.EXPECT().Get(gomock.Any(), "item/1.json").Times(1).DoAndReturn(
+		func(context.Context, string) ([]byte, error) {
+			<-release
+			return []byte(`{"id":1}`), nil
+		},
+	)
+	mockClient.EXPECT().Get(gomock.Any(), "item/2.json").Times(1).Return([]byte(`{"id":2}`), nil)
+	mockClient.EXPECT().Get(gomock.Any(), "item/3.json").Times(1).Return(nil, errors.New("error getting item"))
+
+	h := New(mockClient, itemcache.New(), WithWorkers(3))
+
+	var (
+		mu    sync.Mutex
+		order []int
+	)
+
+	h.GetItemsByIDsFunc(context.Background(), []int{1, 2, 3}, func(n int, it *item.Item, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		order = append(order, n)
+		if len(order) == 2 {
+			close(release)
+		}
+
+		if n == 2 {
+			assert.Nil(t, it)
+			assert.EqualError(t, err, "error getting item")
+
+			return
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, n+1, it.ID)
+	})
+
+	require.Len(t, order, 3)
+	assert.Equal(t, 0, order[2])
+}
+
+func TestHN_GetItemConcurrent(t *testing.T) {
+	t.Run("shares a single request", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockCache := mock_cache.NewMockCache(ctrl)
+		mockClient := mock_client.NewMockHttpClient(ctrl)
+
+		release := make(chan struct{})
+		mockCache.EXPECT().Get(123).AnyTimes().Return(itemcache.Entry{}, false)
+		mockCache.EXPECT().Set(123, gomock.Any()).Times(1)
+		mockClient.EXPECT().Get(gomock.Any(), "item/123.json").Times(1).DoAndReturn(
+			func(context.Context, string) ([]byte, error) {
+				<-release
+				return []byte(`{"id":123}`), nil
+			},
+		)
+
+		h := New(mockClient, mockCache)
+
+		var wg sync.WaitGroup
+		items := make([]*item.Item, 5)
+		for i := range items {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				it, err := h.GetItem(context.Background(), 123)
+				assert.NoError(t, err)
+				items[i] = it
+			}(i)
+		}
+
+		// Let every caller join the request before it completes.
+		require.Eventually(t, func() bool {
+			h.flights.mu.Lock()
+			defer h.flights.mu.Unlock()
+
+			return h.flights.m[123] != nil && h.flights.m[123].callers == len(items)
+		}, time.Second, time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		for _, it := range items {
+			assert.Same(t, items[0], it)
+		}
+	})
+
+	t.Run("keeps fetching for the remaining callers", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockCache := mock_cache.NewMockCache(ctrl)
+		mockClient := mock_client.NewMockHttpClient(ctrl)
+
+		release := make(chan struct{})
+		mockCache.EXPECT().Get(123).AnyTimes().Return(itemcache.Entry{}, false)
+		mockCache.EXPECT().Set(123, gomock.Any()).Times(1)
+		mockClient.EXPECT().Get(gomock.Any(), "item/123.json").Times(1).DoAndReturn(
+			func(ctx context.Context, _ string) ([]byte, error) {
+				<-release
+				return []byte(`{"id":123}`), ctx.Err()
+			},
+		)
+
+		h := New(mockClient, mockCache)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		first := make(chan error)
+		go func() {
+			_, err := h.GetItem(ctx, 123)
+			first <- err
+		}()
+
+		second := make(chan *item.Item)
+		go func() {
+			it, _ := h.GetItem(context.Background(), 123)
+			second <- it
+		}()
+
+		require.Eventually(t, func() bool {
+			h.flights.mu.Lock()
+			defer h.flights.mu.Unlock()
+
+			return h.flights.m[123] != nil && h.flights.m[123].callers == 2
+		}, time.Second, time.Millisecond)
+
+		cancel()
+		assert.ErrorIs(t, <-first, context.Canceled)
+
+		close(release)
+		assert.Equal(t, &item.Item{ID: 123}, <-second)
+	})
+
+	t.Run("cancels the request once every caller gave up", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockCache := mock_cache.NewMockCache(ctrl)
+		mockClient := mock_client.NewMockHttpClient(ctrl)
+
+		canceled := make(chan struct{})
+		mockCache.EXPECT().Get(123).AnyTimes().Return(itemcache.Entry{}, false)
+		mockClient.EXPECT().Get(gomock.Any(), "item/123.json").Times(1).DoAndReturn(
+			func(ctx context.Context, _ string) ([]byte, error) {
+				<-ctx.Done()
+				close(canceled)
+				return nil, ctx.Err()
+			},
+		)
+
+		h := New(mockClient, mockCache)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := h.GetItem(ctx, 123)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			t.Fatal("the request was not canceled")
+		}
+	})
+}
+
+func TestHN_GetUser(t *testing.T) {
+	testCases := []struct {
+		name       string
+		id         string
+		clientStub func(client *mock_client.MockHttpClient)
+		expected   *user.User
+		expectErr  bool
+	}{
+		{
+			name: "success",
+			id:   "pg",
+			clientStub: func(client *mock_client.MockHttpClient) {
+				client.EXPECT().Get(gomock.Any(), "user/pg.json").Times(1).
+					Return([]byte(`{"id":"pg","karma":155111,"created":1160418092,"submitted":[1,2]}`), nil)
+			},
+			expected:  &user.User{ID: "pg", Karma: 155111, Created: 1160418092, Submitted: []int{1, 2}},
+			expectErr: false,
+		},
+		{
+			name: "unknown user",
+			id:   "nobody",
+			clientStub: func(client *mock_client.MockHttpClient) {
+				client.EXPECT().Get(gomock.Any(), "user/nobody.json").Times(1).Return([]byte("null\n"), nil)
+			},
+			expected:  nil,
+			expectErr: true,
+		},
+		{
+			name: "invalid response",
+			id:   "pg",
+			clientStub: func(client *mock_client.MockHttpClient) {
+				client.EXPECT().Get(gomock.Any(), gomock.Any()).Times(1).Return([]byte(`{"invalid"`), nil)
+			},
+			expected:  nil,
+			expectErr: true,
+		},
+		{
+			name: "GET error response",
+			id:   "pg",
+			clientStub: func(client *mock_client.MockHttpClient) {
+				client.EXPECT().Get(gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("get error"))
+			},
+			expected:  nil,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mock_client.NewMockHttpClient(ctrl)
+			tc.clientStub(mockClient)
+
+			h := New(mockClient, nil)
+
+			u, err := h.GetUser(context.Background(), tc.id)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expected, u)
+		})
+	}
+}
+
+func TestHN_GetUpdates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock_client.NewMockHttpClient(ctrl)
+	mockClient.EXPECT().Get(gomock.Any(), constants.UpdateSuffix).Times(1).
+		Return([]byte(`{"items":[1,2],"profiles":["pg"]}`), nil)
+	mockClient.EXPECT().Get(gomock.Any(), constants.UpdateSuffix).Times(1).Return(nil, errors.New("get error"))
+
+	h := New(mockClient, nil)
+
+	u, err := h.GetUpdates(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &Updates{Items: []int{1, 2}, Profiles: []string{"pg"}}, u)
+
+	_, err = h.GetUpdates(context.Background())
+	assert.Error(t, err)
+}
+
+func TestHN_GetMaxItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock_client.NewMockHttpClient(ctrl)
+	mockClient.EXPECT().Get(gomock.Any(), constants.MaxSuffix).Times(1).Return([]byte(`8863`), nil)
+	mockClient.EXPECT().Get(gomock.Any(), constants.MaxSuffix).Times(1).Return([]byte(`{}`), nil)
+
+	h := New(mockClient, nil)
+
+	maxItem, err := h.GetMaxItem(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 8863, maxItem)
+
+	_, err = h.GetMaxItem(context.Background())
+	assert.Error(t, err)
+}
+
+func TestHN_Watch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mock_cache.NewMockCache(ctrl)
+	mockClient := mock_client.NewMockHttpClient(ctrl)
+
+	gomock.InOrder(
+		mockClient.EXPECT().Get(gomock.Any(), constants.UpdateSuffix).Return([]byte(`{"items":[1,2]}`), nil),
+		mockClient.EXPECT().Get(gomock.Any(), constants.MaxSuffix).Return([]byte(`100`), nil),
+		mockClient.EXPECT().Get(gomock.Any(), constants.UpdateSuffix).Return(nil, errors.New("get error")),
+		mockClient.EXPECT().Get(gomock.Any(), constants.UpdateSuffix).Return([]byte(`{"items":[3]}`), nil),
+		mockClient.EXPECT().Get(gomock.Any(), constants.MaxSuffix).Return([]byte(`105`), nil),
+	)
+	// Polls that race with the cancellation below.
+	mockClient.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, context.Canceled).AnyTimes()
+	mockCache.EXPECT().Delete(1).Times(1)
+	mockCache.EXPECT().Delete(2).Times(1)
+	mockCache.EXPECT().Delete(3).Times(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	h := New(mockClient, mockCache)
+	updates := h.Watch(ctx, time.Millisecond)
+
+	u := <-updates
+	assert.Equal(t, []int{1, 2}, u.Items)
+	assert.Equal(t, 100, u.MaxItem)
+	assert.Equal(t, 0, u.NewItems)
+
+	// The failed poll is skipped.
+	u = <-updates
+	assert.Equal(t, []int{3}, u.Items)
+	assert.Equal(t, 105, u.MaxItem)
+	assert.Equal(t, 5, u.NewItems)
+
+	cancel()
+
+	for range updates {
+	}
+}
+
+func TestHN_StreamItems(t *testing.T) {
+	t.Run("put and patch events", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "event: put\ndata: {\"path\":\"/\",\"data\":[1,2,3]}\n\n")
+			fmt.Fprint(w, "event: patch\ndata: {\"path\":\"/\",\"data\":{\"0\":4,\"3\":5}}\n\n")
+			fmt.Fprint(w, "event: put\ndata: {\"path\":\"/1\",\"data\":6}\n\n")
+		}))
+		defer ts.Close()
+
+		h := New(httpclient.New(ts.URL, &http.Client{}), nil)
+
+		var lists [][]int
+		err := h.StreamItems(context.Background(), constants.Items.TopItems, func(ids []int) {
+			lists = append(lists, ids)
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, [][]int{{1, 2, 3}, {4, 2, 3, 5}, {4, 6, 3, 5}}, lists)
+	})
+
+	t.Run("deleted entries", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			events   string
+			expected []int
+		}{
+			"trailing put": {
+				events:   "event: put\ndata: {\"path\":\"/2\",\"data\":null}\n\n",
+				expected: []int{10, 11},
+			},
+			"put in the middle": {
+				events:   "event: put\ndata: {\"path\":\"/1\",\"data\":null}\n\n",
+				expected: []int{10, 12},
+			},
+			"patch": {
+				events:   "event: patch\ndata: {\"path\":\"/\",\"data\":{\"1\":null,\"2\":null}}\n\n",
+				expected: []int{10},
+			},
+			"whole list": {
+				events:   "event: put\ndata: {\"path\":\"/\",\"data\":[13,null,14]}\n\n",
+				expected: []int{13, 14},
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					fmt.Fprint(w, "event: put\ndata: {\"path\":\"/\",\"data\":[10,11,12]}\n\n")
+					fmt.Fprint(w, tc.events)
+				}))
+				defer ts.Close()
+
+				h := New(httpclient.New(ts.URL, &http.Client{}), nil)
+
+				var last []int
+				err := h.StreamItems(context.Background(), constants.Items.TopItems, func(ids []int) {
+					last = ids
+				})
+				require.NoError(t, err)
+
+				assert.Equal(t, tc.expected, last)
+			})
+		}
+	})
+
+	t.Run("put after a deleted entry", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "event: put\ndata: {\"path\":\"/\",\"data\":[10,11,12]}\n\n")
+			fmt.Fprint(w, "event: put\ndata: {\"path\":\"/1\",\"data\":null}\n\n")
+			fmt.Fprint(w, "event: put\ndata: {\"path\":\"/2\",\"data\":13}\n\n")
+		}))
+		defer ts.Close()
+
+		h := New(httpclient.New(ts.URL, &http.Client{}), nil)
+
+		var lists [][]int
+		err := h.StreamItems(context.Background(), constants.Items.TopItems, func(ids []int) {
+			lists = append(lists, ids)
+		})
+		require.NoError(t, err)
+
+		// The ranks of the entries after the deleted one are kept.
+		assert.Equal(t, [][]int{{10, 11, 12}, {10, 12}, {10, 13}}, lists)
+	})
+
+	t.Run("wrong item type", func(t *testing.T) {
+		h := New(httpclient.New("", &http.Client{}), nil)
+
+		err := h.StreamItems(context.Background(), "", func(ids []int) {})
+		assert.ErrorIs(t, err, ErrInvalidItemType)
+	})
+
+	t.Run("stream error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockClient := mock_client.NewMockHttpClient(ctrl)
+		mockClient.EXPECT().Stream(gomock.Any(), constants.TopSuffix, gomock.Any()).Return(errors.New("stream error"))
+
+		h := New(mockClient, nil)
+
+		err := h.StreamItems(context.Background(), constants.Items.TopItems, func(ids []int) {})
+		assert.EqualError(t, err, "stream error")
+	})
+}
+
+func TestHN_WatchFeeds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock_client.NewMockHttpClient(ctrl)
+
+	// The stream of the top stories ends after a single event, and is opened again.
+	mockClient.EXPECT().Stream(gomock.Any(), constants.TopSuffix, gomock.Any()).MinTimes(2).DoAndReturn(
+		func(_ context.Context, _ string, handle func(httpclient.Event) error) error {
+			return handle(httpclient.Event{Type: httpclient.EventPut, Path: "/", Data: []byte(`[1,2,3]`)})
+		},
+	)
+
+	feeds, err := itemcache.NewDisk(t.TempDir(), 0, 0)
+	require.NoError(t, err)
+	t.Cleanup(feeds.Close)
+
+	h := New(mockClient, nil, WithFeedCache(feeds))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	changes := h.WatchFeeds(ctx, time.Millisecond, constants.Items.TopItems)
+	for i := 0; i < 2; i++ {
+		select {
+		case f := <-changes:
+			assert.Equal(t, Feed{Type: constants.Items.TopItems, IDs: []int{1, 2, 3}}, f)
+		case <-time.After(time.Second):
+			t.Fatal("the feed was not sent")
+		}
+	}
+
+	e, ok := feeds.GetFeed(string(constants.Items.TopItems))
+	require.True(t, ok)
+	assert.Equal(t, []int{1, 2, 3}, e.IDs)
+
+	cancel()
+
+	// The channel is closed once the context is done.
+	for range changes {
+	}
+}
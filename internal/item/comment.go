@@ -0,0 +1,36 @@
+package item
+
+// Comment is a node of a discussion tree.
+// It wraps the underlying item together with its depth in the thread and its replies.
+type Comment struct {
+	*Item
+	Depth     int
+	Children  []*Comment
+	Collapsed bool
+}
+
+// Count returns the number of replies beneath the comment, at any depth.
+func (c *Comment) Count() int {
+	count := len(c.Children)
+	for _, child := range c.Children {
+		count += child.Count()
+	}
+
+	return count
+}
+
+// Siblings returns the comments of the tree that share a parent with target,
+// including target itself. It returns nil if target is not part of the tree.
+func Siblings(tree []*Comment, target *Comment) []*Comment {
+	for _, c := range tree {
+		if c == target {
+			return tree
+		}
+
+		if siblings := Siblings(c.Children, target); siblings != nil {
+			return siblings
+		}
+	}
+
+	return nil
+}
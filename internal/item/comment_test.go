@@ -0,0 +1,33 @@
+package item
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComment_Count(t *testing.T) {
+	c := &Comment{
+		Item: &Item{ID: 1},
+		Children: []*Comment{
+			{Item: &Item{ID: 2}, Children: []*Comment{{Item: &Item{ID: 4}}}},
+			{Item: &Item{ID: 3}},
+		},
+	}
+
+	assert.Equal(t, 3, c.Count())
+	assert.Equal(t, 1, c.Children[0].Count())
+	assert.Equal(t, 0, c.Children[1].Count())
+}
+
+func TestSiblings(t *testing.T) {
+	leaf := &Comment{Item: &Item{ID: 4}}
+	tree := []*Comment{
+		{Item: &Item{ID: 2}, Children: []*Comment{leaf, {Item: &Item{ID: 5}}}},
+		{Item: &Item{ID: 3}},
+	}
+
+	assert.Equal(t, tree, Siblings(tree, tree[1]))
+	assert.Equal(t, tree[0].Children, Siblings(tree, leaf))
+	assert.Nil(t, Siblings(tree, &Comment{Item: &Item{ID: 4}}))
+}
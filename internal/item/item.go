@@ -2,6 +2,9 @@ package item
 
 import (
 	"fmt"
+	"html"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
@@ -10,6 +13,13 @@ import (
 	"github.com/KarolosLykos/hackertea/internal/tui/theme"
 )
 
+const snippetLength = 80
+
+var (
+	tagRegexp       = regexp.MustCompile(`<[^>]*>`)
+	paragraphRegexp = regexp.MustCompile(`(?i)<p\s*/?>`)
+)
+
 type Item struct {
 	ID          int    `json:"id"`
 	Parent      int    `json:"parent"`
@@ -25,15 +35,24 @@ type Item struct {
 	URL         string `json:"url"`
 	Dead        bool   `json:"dead"`
 	Deleted     bool   `json:"deleted"`
-	Visited     bool
+	Visited     bool   `json:"-"`
 }
 
 func (i *Item) Time() time.Time {
 	return time.Unix(int64(i.Timestamp), 0)
 }
 
+// Ago returns the time elapsed since the item was posted.
+func (i *Item) Ago() string {
+	return constants.CurrentTime.Sub(i.Time()).Round(time.Second).String()
+}
+
 func (i *Item) Title() string {
 	text := fmt.Sprintf("%s", i.Titl)
+	if text == "" && i.Text != "" {
+		text = snippet(HTMLToText(i.Text))
+	}
+
 	if i.URL != "" {
 		text = fmt.Sprintf("%s (%s)", i.Titl, i.URL)
 	}
@@ -46,11 +65,15 @@ func (i *Item) Title() string {
 }
 
 func (i *Item) Description() string {
+	if i.Type == "job" {
+		return i.jobDescription()
+	}
+
 	desc := fmt.Sprintf(
 		"%d points by %s %s ago %d comments",
 		i.Score,
 		i.By,
-		constants.CurrentTime.Sub(i.Time()).Round(time.Second).String(),
+		i.Ago(),
 		i.Descendants,
 	)
 
@@ -61,8 +84,39 @@ func (i *Item) Description() string {
 	return desc
 }
 
+// jobDescription describes a job posting, which has neither a score nor comments.
+func (i *Item) jobDescription() string {
+	desc := fmt.Sprintf("job posted %s ago", i.Ago())
+
+	if i.Visited {
+		return visitedStyle().Render(desc)
+	}
+
+	return desc
+}
+
 func (i *Item) FilterValue() string { return i.Titl }
 
+// HTMLToText converts the HTML used by the Hacker News API for comments,
+// story texts and user profiles into plain text, turning paragraphs into blank lines.
+func HTMLToText(s string) string {
+	s = paragraphRegexp.ReplaceAllString(s, "\n\n")
+	s = tagRegexp.ReplaceAllString(s, "")
+
+	return strings.TrimSpace(html.UnescapeString(s))
+}
+
+// snippet returns the first line of text, shortened to snippetLength runes.
+func snippet(text string) string {
+	text, _, _ = strings.Cut(text, "\n")
+
+	if runes := []rune(text); len(runes) > snippetLength {
+		return string(runes[:snippetLength-1]) + "…"
+	}
+
+	return text
+}
+
 func visitedStyle() lipgloss.Style {
 	t, _ := theme.GetTheme()
 
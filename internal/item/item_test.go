@@ -2,6 +2,7 @@ package item
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -20,6 +21,11 @@ func TestItem_Time(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestItem_Ago(t *testing.T) {
+	item := Item{Timestamp: int(constants.CurrentTime.Add(-time.Hour).Unix())}
+	assert.Equal(t, constants.CurrentTime.Sub(item.Time()).Round(time.Second).String(), item.Ago())
+}
+
 func TestItem_Title(t *testing.T) {
 	item := Item{Titl: "Test Title", URL: "https://example.com"}
 	expected := "Test Title (https://example.com)"
@@ -30,6 +36,20 @@ func TestItem_Title(t *testing.T) {
 	assert.Equal(t, expected, item.Title())
 }
 
+func TestItem_TitleFromText(t *testing.T) {
+	item := Item{Text: "It&#x27;s a comment<p>with a second paragraph"}
+	assert.Equal(t, "It's a comment", item.Title())
+
+	item = Item{Text: strings.Repeat("a", 100)}
+	assert.Equal(t, strings.Repeat("a", 79)+"…", item.Title())
+}
+
+func TestHTMLToText(t *testing.T) {
+	assert.Equal(t, "", HTMLToText(""))
+	assert.Equal(t, "It's a <test>", HTMLToText("It&#x27;s a &lt;test&gt;"))
+	assert.Equal(t, "first\n\nsecond link", HTMLToText(`first<p>second <a href="https://example.com">link</a>`))
+}
+
 func TestItem_Description(t *testing.T) {
 	item := Item{
 		Score:       42,
@@ -46,6 +66,19 @@ func TestItem_Description(t *testing.T) {
 	assert.Equal(t, expected, item.Description())
 }
 
+func TestItem_JobDescription(t *testing.T) {
+	item := Item{
+		Type:      "job",
+		By:        "company",
+		Timestamp: int(time.Date(2023, 4, 30, 0, 0, 0, 0, time.Local).Unix()),
+	}
+	expected := fmt.Sprintf("job posted %s ago", item.Ago())
+	assert.Equal(t, expected, item.Description())
+
+	item.Visited = true
+	assert.Equal(t, visitedStyle().Render(expected), item.Description())
+}
+
 func TestItem_FilterValue(t *testing.T) {
 	item := Item{Titl: "Test Title"}
 	assert.Equal(t, "Test Title", item.FilterValue())
@@ -7,7 +7,7 @@ package mock_cache
 import (
 	reflect "reflect"
 
-	item "github.com/KarolosLykos/hackertea/internal/item"
+	cache "github.com/KarolosLykos/hackertea/internal/cache"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -34,11 +34,23 @@ func (m *MockCache) EXPECT() *MockCacheMockRecorder {
 	return m.recorder
 }
 
+// Delete mocks base method.
+func (m *MockCache) Delete(key int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Delete", key)
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCacheMockRecorder) Delete(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCache)(nil).Delete), key)
+}
+
 // Get mocks base method.
-func (m *MockCache) Get(key int) (*item.Item, bool) {
+func (m *MockCache) Get(key int) (cache.Entry, bool) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Get", key)
-	ret0, _ := ret[0].(*item.Item)
+	ret0, _ := ret[0].(cache.Entry)
 	ret1, _ := ret[1].(bool)
 	return ret0, ret1
 }
@@ -50,13 +62,13 @@ func (mr *MockCacheMockRecorder) Get(key interface{}) *gomock.Call {
 }
 
 // Set mocks base method.
-func (m *MockCache) Set(key int, value *item.Item) {
+func (m *MockCache) Set(key int, e cache.Entry) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Set", key, value)
+	m.ctrl.Call(m, "Set", key, e)
 }
 
 // Set indicates an expected call of Set.
-func (mr *MockCacheMockRecorder) Set(key, value interface{}) *gomock.Call {
+func (mr *MockCacheMockRecorder) Set(key, e interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockCache)(nil).Set), key, value)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockCache)(nil).Set), key, e)
 }
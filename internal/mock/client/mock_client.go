@@ -8,6 +8,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	client "github.com/KarolosLykos/hackertea/internal/client"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -48,3 +49,17 @@ func (mr *MockHttpClientMockRecorder) Get(ctx, suffix interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockHttpClient)(nil).Get), ctx, suffix)
 }
+
+// Stream mocks base method.
+func (m *MockHttpClient) Stream(ctx context.Context, suffix string, handle func(client.Event) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", ctx, suffix, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockHttpClientMockRecorder) Stream(ctx, suffix, handle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockHttpClient)(nil).Stream), ctx, suffix, handle)
+}
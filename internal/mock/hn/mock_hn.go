@@ -9,7 +9,9 @@ import (
 	reflect "reflect"
 
 	constants "github.com/KarolosLykos/hackertea/internal/constants"
+	hn "github.com/KarolosLykos/hackertea/internal/hn"
 	item "github.com/KarolosLykos/hackertea/internal/item"
+	user "github.com/KarolosLykos/hackertea/internal/user"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -65,3 +67,89 @@ func (mr *MockServiceMockRecorder) GetItems(ctx, item interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockService)(nil).GetItems), ctx, item)
 }
+
+// GetItemsByIDs mocks base method.
+func (m *MockService) GetItemsByIDs(ctx context.Context, ids []int) ([]*item.Item, []error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemsByIDs", ctx, ids)
+	ret0, _ := ret[0].([]*item.Item)
+	ret1, _ := ret[1].([]error)
+	return ret0, ret1
+}
+
+// GetItemsByIDs indicates an expected call of GetItemsByIDs.
+func (mr *MockServiceMockRecorder) GetItemsByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemsByIDs", reflect.TypeOf((*MockService)(nil).GetItemsByIDs), ctx, ids)
+}
+
+// GetItemsByIDsFunc mocks base method.
+func (m *MockService) GetItemsByIDsFunc(ctx context.Context, ids []int, fn func(int, *item.Item, error)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetItemsByIDsFunc", ctx, ids, fn)
+}
+
+// GetItemsByIDsFunc indicates an expected call of GetItemsByIDsFunc.
+func (mr *MockServiceMockRecorder) GetItemsByIDsFunc(ctx, ids, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemsByIDsFunc", reflect.TypeOf((*MockService)(nil).GetItemsByIDsFunc), ctx, ids, fn)
+}
+
+// GetMaxItem mocks base method.
+func (m *MockService) GetMaxItem(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaxItem", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMaxItem indicates an expected call of GetMaxItem.
+func (mr *MockServiceMockRecorder) GetMaxItem(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxItem", reflect.TypeOf((*MockService)(nil).GetMaxItem), ctx)
+}
+
+// GetUpdates mocks base method.
+func (m *MockService) GetUpdates(ctx context.Context) (*hn.Updates, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpdates", ctx)
+	ret0, _ := ret[0].(*hn.Updates)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUpdates indicates an expected call of GetUpdates.
+func (mr *MockServiceMockRecorder) GetUpdates(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpdates", reflect.TypeOf((*MockService)(nil).GetUpdates), ctx)
+}
+
+// GetUser mocks base method.
+func (m *MockService) GetUser(ctx context.Context, id string) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", ctx, id)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockServiceMockRecorder) GetUser(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockService)(nil).GetUser), ctx, id)
+}
+
+// StreamItems mocks base method.
+func (m *MockService) StreamItems(ctx context.Context, item constants.ItemType, fn func([]int)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamItems", ctx, item, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamItems indicates an expected call of StreamItems.
+func (mr *MockServiceMockRecorder) StreamItems(ctx, item, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamItems", reflect.TypeOf((*MockService)(nil).StreamItems), ctx, item, fn)
+}
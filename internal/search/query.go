@@ -0,0 +1,63 @@
+package search
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayout is the layout of the dates accepted by the after: and before: filters.
+const dateLayout = "2006-01-02"
+
+// ParseQuery parses the text typed in the search input into a Query.
+// Besides free text, it understands the following filters:
+//   - tag:<tag> restricts the results to a tag such as story, comment, ask_hn or show_hn.
+//   - author:<id> restricts the results to a user.
+//   - points:<n> only keeps results with at least n points.
+//   - after:<yyyy-mm-dd> and before:<yyyy-mm-dd> restrict the creation date.
+//   - sort:date sorts the results by date instead of relevance.
+func ParseQuery(s string) (Query, error) {
+	q := Query{}
+
+	var words []string
+
+	for _, field := range strings.Fields(s) {
+		name, value, ok := strings.Cut(field, ":")
+		if !ok || value == "" {
+			words = append(words, field)
+			continue
+		}
+
+		var err error
+
+		switch name {
+		case "tag":
+			q.Tags = append(q.Tags, value)
+		case "author":
+			q.Author = value
+		case "points":
+			q.MinPoints, err = strconv.Atoi(value)
+		case "after":
+			q.After, err = time.ParseInLocation(dateLayout, value, time.Local)
+		case "before":
+			q.Before, err = time.ParseInLocation(dateLayout, value, time.Local)
+		case "sort":
+			if value != "date" {
+				err = fmt.Errorf("unknown sort order %q", value)
+			}
+
+			q.ByDate = true
+		default:
+			words = append(words, field)
+		}
+
+		if err != nil {
+			return Query{}, fmt.Errorf("invalid filter %s: %w", field, err)
+		}
+	}
+
+	q.Text = strings.Join(words, " ")
+
+	return q, nil
+}
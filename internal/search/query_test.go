@@ -0,0 +1,50 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	tt := []struct {
+		name      string
+		input     string
+		expected  Query
+		expectErr bool
+	}{
+		{name: "text only", input: "rust  compiler", expected: Query{Text: "rust compiler"}},
+		{
+			name:  "all filters",
+			input: "rust tag:story tag:show_hn author:pg points:100 after:2023-01-01 before:2024-01-01 sort:date",
+			expected: Query{
+				Text:      "rust",
+				Tags:      []string{"story", "show_hn"},
+				Author:    "pg",
+				MinPoints: 100,
+				After:     time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local),
+				Before:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local),
+				ByDate:    true,
+			},
+		},
+		{name: "unknown filter is text", input: "lang:go http:", expected: Query{Text: "lang:go http:"}},
+		{name: "invalid points", input: "points:many", expectErr: true},
+		{name: "invalid date", input: "after:yesterday", expectErr: true},
+		{name: "invalid sort", input: "sort:points", expectErr: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := ParseQuery(tc.input)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, q)
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Package search queries the Algolia Hacker News Search API.
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KarolosLykos/hackertea/internal/client"
+	"github.com/KarolosLykos/hackertea/internal/constants"
+	"github.com/KarolosLykos/hackertea/internal/item"
+)
+
+// Service interface that specifies the behavior of a search service.
+type Service interface {
+	Search(ctx context.Context, q Query) (*Result, error)
+}
+
+// Query describes a search and its filters.
+type Query struct {
+	Text      string
+	Tags      []string
+	Author    string
+	MinPoints int
+	After     time.Time
+	Before    time.Time
+	ByDate    bool
+	Page      int
+}
+
+// Result is a page of search results.
+type Result struct {
+	Items   []*item.Item
+	Hits    int
+	Page    int
+	NbPages int
+}
+
+// Algolia implements the Service interface using the Algolia Hacker News Search API.
+type Algolia struct {
+	c client.HttpClient
+}
+
+// New returns a new Algolia search service that sends its requests through c.
+// The base URL of c decides which server is queried.
+func New(c client.HttpClient) *Algolia {
+	return &Algolia{c: c}
+}
+
+// hit is a single search result as returned by the API.
+type hit struct {
+	ObjectID    string   `json:"objectID"`
+	Title       string   `json:"title"`
+	URL         string   `json:"url"`
+	Author      string   `json:"author"`
+	Points      int      `json:"points"`
+	NumComments int      `json:"num_comments"`
+	CreatedAt   int      `json:"created_at_i"`
+	StoryText   string   `json:"story_text"`
+	CommentText string   `json:"comment_text"`
+	ParentID    int      `json:"parent_id"`
+	Tags        []string `json:"_tags"`
+}
+
+type response struct {
+	Hits    []hit `json:"hits"`
+	NbHits  int   `json:"nbHits"`
+	Page    int   `json:"page"`
+	NbPages int   `json:"nbPages"`
+}
+
+// Search runs the given query and returns the requested page of results.
+func (a *Algolia) Search(ctx context.Context, q Query) (*Result, error) {
+	resp, err := a.c.Get(ctx, q.suffix())
+	if err != nil {
+		return nil, err
+	}
+
+	r := &response{}
+	if err = json.Unmarshal(resp, r); err != nil {
+		return nil, err
+	}
+
+	items := make([]*item.Item, 0, len(r.Hits))
+	for _, h := range r.Hits {
+		id, err := strconv.Atoi(h.ObjectID)
+		if err != nil {
+			continue
+		}
+
+		items = append(items, h.item(id))
+	}
+
+	return &Result{Items: items, Hits: r.NbHits, Page: r.Page, NbPages: r.NbPages}, nil
+}
+
+// suffix returns the endpoint and query string of the search.
+func (q Query) suffix() string {
+	endpoint := constants.SearchSuffix
+	if q.ByDate {
+		endpoint = constants.SearchByDateSuffix
+	}
+
+	values := url.Values{}
+	values.Set("query", q.Text)
+
+	tags := append([]string(nil), q.Tags...)
+	if q.Author != "" {
+		tags = append(tags, "author_"+q.Author)
+	}
+
+	if len(tags) > 0 {
+		values.Set("tags", strings.Join(tags, ","))
+	}
+
+	var filters []string
+	if q.MinPoints > 0 {
+		filters = append(filters, fmt.Sprintf("points>=%d", q.MinPoints))
+	}
+
+	if !q.After.IsZero() {
+		filters = append(filters, fmt.Sprintf("created_at_i>=%d", q.After.Unix()))
+	}
+
+	if !q.Before.IsZero() {
+		filters = append(filters, fmt.Sprintf("created_at_i<%d", q.Before.Unix()))
+	}
+
+	if len(filters) > 0 {
+		values.Set("numericFilters", strings.Join(filters, ","))
+	}
+
+	if q.Page > 0 {
+		values.Set("page", strconv.Itoa(q.Page))
+	}
+
+	return fmt.Sprintf("%s?%s", endpoint, values.Encode())
+}
+
+// item converts the hit into an item, so it can be shown like any other story.
+func (h hit) item(id int) *item.Item {
+	it := &item.Item{
+		ID:          id,
+		Parent:      h.ParentID,
+		Titl:        h.Title,
+		URL:         h.URL,
+		By:          h.Author,
+		Score:       h.Points,
+		Descendants: h.NumComments,
+		Timestamp:   h.CreatedAt,
+		Text:        h.StoryText,
+		Type:        "story",
+	}
+
+	for _, tag := range h.Tags {
+		switch tag {
+		case "comment", "poll", "pollopt", "job":
+			it.Type = tag
+		}
+	}
+
+	if h.CommentText != "" {
+		it.Text = h.CommentText
+	}
+
+	return it
+}
@@ -0,0 +1,108 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/hackertea/internal/client"
+	"github.com/KarolosLykos/hackertea/internal/item"
+	mock_client "github.com/KarolosLykos/hackertea/internal/mock/client"
+)
+
+func TestAlgolia_Search(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		after := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/search_by_date", r.URL.Path)
+			assert.Equal(t, "rust", r.URL.Query().Get("query"))
+			assert.Equal(t, "story,author_pg", r.URL.Query().Get("tags"))
+			assert.Equal(t, fmt.Sprintf("points>=10,created_at_i>=%d", after.Unix()), r.URL.Query().Get("numericFilters"))
+			assert.Equal(t, "2", r.URL.Query().Get("page"))
+
+			fmt.Fprint(w, `{
+				"hits": [
+					{"objectID": "1", "title": "Rust", "url": "https://example.com", "author": "pg",
+					 "points": 42, "num_comments": 3, "created_at_i": 1700000000, "_tags": ["story", "author_pg"]},
+					{"objectID": "2", "author": "pg", "comment_text": "A comment", "parent_id": 1,
+					 "created_at_i": 1700000001, "_tags": ["comment", "author_pg"]},
+					{"objectID": "invalid"}
+				],
+				"nbHits": 3, "page": 2, "nbPages": 5
+			}`)
+		}))
+		defer ts.Close()
+
+		s := New(client.New(ts.URL, &http.Client{}))
+
+		res, err := s.Search(context.Background(), Query{
+			Text:      "rust",
+			Tags:      []string{"story"},
+			Author:    "pg",
+			MinPoints: 10,
+			After:     after,
+			ByDate:    true,
+			Page:      2,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, res.Hits)
+		assert.Equal(t, 2, res.Page)
+		assert.Equal(t, 5, res.NbPages)
+		assert.Equal(t, []*item.Item{
+			{
+				ID: 1, Titl: "Rust", URL: "https://example.com", By: "pg",
+				Score: 42, Descendants: 3, Timestamp: 1700000000, Type: "story",
+			},
+			{ID: 2, Parent: 1, By: "pg", Text: "A comment", Timestamp: 1700000001, Type: "comment"},
+		}, res.Items)
+	})
+
+	t.Run("relevance endpoint", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/search", r.URL.Path)
+			assert.Empty(t, r.URL.Query().Get("tags"))
+			assert.Empty(t, r.URL.Query().Get("numericFilters"))
+
+			fmt.Fprint(w, `{"hits": []}`)
+		}))
+		defer ts.Close()
+
+		s := New(client.New(ts.URL, &http.Client{}))
+
+		res, err := s.Search(context.Background(), Query{Text: "go"})
+		require.NoError(t, err)
+		assert.Empty(t, res.Items)
+	})
+
+	t.Run("client error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockClient := mock_client.NewMockHttpClient(ctrl)
+		mockClient.EXPECT().Get(gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("client error"))
+
+		_, err := New(mockClient).Search(context.Background(), Query{Text: "go"})
+		assert.ErrorContains(t, err, "client error")
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockClient := mock_client.NewMockHttpClient(ctrl)
+		mockClient.EXPECT().Get(gomock.Any(), gomock.Any()).Times(1).Return([]byte(`{invalid`), nil)
+
+		_, err := New(mockClient).Search(context.Background(), Query{Text: "go"})
+		assert.Error(t, err)
+	})
+}
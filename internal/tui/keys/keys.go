@@ -5,11 +5,14 @@ import (
 )
 
 type listKeyMap struct {
-	nextPage      key.Binding
-	previousPage  key.Binding
-	nextTab       key.Binding
-	previousTab   key.Binding
-	fetchNextPage key.Binding
+	nextPage     key.Binding
+	previousPage key.Binding
+	nextTab      key.Binding
+	previousTab  key.Binding
+	comments     key.Binding
+	user         key.Binding
+	retry        key.Binding
+	debug        key.Binding
 }
 
 func NewListKeyMap() *listKeyMap {
@@ -30,9 +33,21 @@ func NewListKeyMap() *listKeyMap {
 			key.WithKeys("T", "shift+tab"),
 			key.WithHelp("T/Shift+Tab", "previous tab"),
 		),
-		fetchNextPage: key.NewBinding(
-			key.WithKeys("n"),
-			key.WithHelp("n", "next page"),
+		comments: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "comments"),
+		),
+		user: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "author"),
+		),
+		retry: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "retry"),
+		),
+		debug: key.NewBinding(
+			key.WithKeys("ctrl+d"),
+			key.WithHelp("ctrl+d", "debug"),
 		),
 	}
 }
@@ -44,7 +59,192 @@ func (l *listKeyMap) KeyBindings() func() []key.Binding {
 			l.previousPage,
 			l.nextTab,
 			l.previousTab,
-			l.fetchNextPage,
+			l.comments,
+			l.user,
+			l.retry,
+			l.debug,
+		}
+	}
+}
+
+type commentsKeyMap struct {
+	up   key.Binding
+	down key.Binding
+	top  key.Binding
+	end  key.Binding
+	fold key.Binding
+	all  key.Binding
+	user key.Binding
+	back key.Binding
+	quit key.Binding
+}
+
+func NewCommentsKeyMap() *commentsKeyMap {
+	return &commentsKeyMap{
+		up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		top: key.NewBinding(
+			key.WithKeys("home", "g"),
+			key.WithHelp("g/home", "go to start"),
+		),
+		end: key.NewBinding(
+			key.WithKeys("end", "G"),
+			key.WithHelp("G/end", "go to end"),
+		),
+		fold: key.NewBinding(
+			key.WithKeys(" ", "enter"),
+			key.WithHelp("space", "collapse/expand"),
+		),
+		all: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "collapse/expand level"),
+		),
+		user: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "author"),
+		),
+		back: key.NewBinding(
+			key.WithKeys("esc", "backspace"),
+			key.WithHelp("esc", "back"),
+		),
+		quit: key.NewBinding(
+			key.WithKeys("q", "ctrl+c"),
+			key.WithHelp("q", "quit"),
+		),
+	}
+}
+
+// ShortHelp returns the key bindings shown in the short help view of the comments screen.
+func (c *commentsKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{c.up, c.down, c.fold, c.back, c.quit}
+}
+
+// FullHelp returns the key bindings shown in the full help view of the comments screen.
+func (c *commentsKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{c.up, c.down, c.top, c.end},
+		{c.fold, c.all, c.user},
+		{c.back, c.quit},
+	}
+}
+
+type profileKeyMap struct {
+	fetchNextPage key.Binding
+	comments      key.Binding
+	back          key.Binding
+}
+
+func NewProfileKeyMap() *profileKeyMap {
+	return &profileKeyMap{
+		fetchNextPage: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next page"),
+		),
+		comments: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "comments"),
+		),
+		back: key.NewBinding(
+			key.WithKeys("esc", "backspace"),
+			key.WithHelp("esc", "back"),
+		),
+	}
+}
+
+func (p *profileKeyMap) KeyBindings() func() []key.Binding {
+	return func() []key.Binding {
+		return []key.Binding{
+			p.fetchNextPage,
+			p.comments,
+			p.back,
+		}
+	}
+}
+
+type pollKeyMap struct {
+	scroll   key.Binding
+	comments key.Binding
+	user     key.Binding
+	back     key.Binding
+	quit     key.Binding
+}
+
+func NewPollKeyMap() *pollKeyMap {
+	return &pollKeyMap{
+		scroll: key.NewBinding(
+			key.WithKeys("up", "k", "down", "j"),
+			key.WithHelp("↑/↓", "scroll"),
+		),
+		comments: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "comments"),
+		),
+		user: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "author"),
+		),
+		back: key.NewBinding(
+			key.WithKeys("esc", "backspace"),
+			key.WithHelp("esc", "back"),
+		),
+		quit: key.NewBinding(
+			key.WithKeys("q", "ctrl+c"),
+			key.WithHelp("q", "quit"),
+		),
+	}
+}
+
+// ShortHelp returns the key bindings shown in the short help view of the poll screen.
+func (p *pollKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{p.scroll, p.comments, p.user, p.back, p.quit}
+}
+
+// FullHelp returns the key bindings shown in the full help view of the poll screen.
+func (p *pollKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{p.ShortHelp()}
+}
+
+type searchKeyMap struct {
+	search        key.Binding
+	fetchNextPage key.Binding
+	comments      key.Binding
+	user          key.Binding
+}
+
+func NewSearchKeyMap() *searchKeyMap {
+	return &searchKeyMap{
+		search: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "search"),
+		),
+		fetchNextPage: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next page"),
+		),
+		comments: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "comments"),
+		),
+		user: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "author"),
+		),
+	}
+}
+
+func (s *searchKeyMap) KeyBindings() func() []key.Binding {
+	return func() []key.Binding {
+		return []key.Binding{
+			s.search,
+			s.fetchNextPage,
+			s.comments,
+			s.user,
 		}
 	}
 }
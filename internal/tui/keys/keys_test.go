@@ -14,14 +14,64 @@ func TestListKeyMap(t *testing.T) {
 	assert.NotNil(t, listKeys.previousPage)
 	assert.NotNil(t, listKeys.nextTab)
 	assert.NotNil(t, listKeys.previousTab)
-	assert.NotNil(t, listKeys.fetchNextPage)
+	assert.NotNil(t, listKeys.retry)
+	assert.NotNil(t, listKeys.comments)
+	assert.NotNil(t, listKeys.user)
 
 	// Test KeyBindings
 	bindings := listKeys.KeyBindings()
-	assert.Equal(t, 5, len(bindings()))
+	assert.Equal(t, 8, len(bindings()))
 	assert.Contains(t, bindings(), listKeys.nextPage)
 	assert.Contains(t, bindings(), listKeys.previousPage)
 	assert.Contains(t, bindings(), listKeys.nextTab)
 	assert.Contains(t, bindings(), listKeys.previousTab)
-	assert.Contains(t, bindings(), listKeys.fetchNextPage)
+	assert.Contains(t, bindings(), listKeys.retry)
+	assert.Contains(t, bindings(), listKeys.comments)
+	assert.Contains(t, bindings(), listKeys.user)
+}
+
+func TestCommentsKeyMap(t *testing.T) {
+	commentsKeys := NewCommentsKeyMap()
+
+	assert.Len(t, commentsKeys.ShortHelp(), 5)
+	assert.Contains(t, commentsKeys.ShortHelp(), commentsKeys.back)
+	assert.Contains(t, commentsKeys.ShortHelp(), commentsKeys.fold)
+
+	assert.Len(t, commentsKeys.FullHelp(), 3)
+	assert.Contains(t, commentsKeys.FullHelp()[1], commentsKeys.all)
+	assert.Contains(t, commentsKeys.FullHelp()[1], commentsKeys.user)
+	assert.Contains(t, commentsKeys.FullHelp()[0], commentsKeys.top)
+	assert.Contains(t, commentsKeys.FullHelp()[0], commentsKeys.end)
+}
+
+func TestProfileKeyMap(t *testing.T) {
+	profileKeys := NewProfileKeyMap()
+
+	bindings := profileKeys.KeyBindings()
+	assert.Equal(t, 3, len(bindings()))
+	assert.Contains(t, bindings(), profileKeys.fetchNextPage)
+	assert.Contains(t, bindings(), profileKeys.comments)
+	assert.Contains(t, bindings(), profileKeys.back)
+}
+
+func TestPollKeyMap(t *testing.T) {
+	pollKeys := NewPollKeyMap()
+
+	assert.Len(t, pollKeys.ShortHelp(), 5)
+	assert.Contains(t, pollKeys.ShortHelp(), pollKeys.scroll)
+	assert.Contains(t, pollKeys.ShortHelp(), pollKeys.comments)
+	assert.Contains(t, pollKeys.ShortHelp(), pollKeys.back)
+
+	assert.Len(t, pollKeys.FullHelp(), 1)
+}
+
+func TestSearchKeyMap(t *testing.T) {
+	searchKeys := NewSearchKeyMap()
+
+	bindings := searchKeys.KeyBindings()
+	assert.Equal(t, 4, len(bindings()))
+	assert.Contains(t, bindings(), searchKeys.search)
+	assert.Contains(t, bindings(), searchKeys.fetchNextPage)
+	assert.Contains(t, bindings(), searchKeys.comments)
+	assert.Contains(t, bindings(), searchKeys.user)
 }
@@ -1,36 +1,233 @@
 package model
 
 import (
+	"context"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/KarolosLykos/hackertea/internal/hn"
+	"github.com/KarolosLykos/hackertea/internal/item"
+	"github.com/KarolosLykos/hackertea/internal/search"
+	"github.com/KarolosLykos/hackertea/internal/user"
 	"github.com/KarolosLykos/hackertea/internal/utils"
 )
 
-func (m model) initCmd() tea.Cmd {
+// fetchFeed fetches the story IDs of the given tab.
+func (m model) fetchFeed(tabID int) tea.Cmd {
+	return func() tea.Msg {
+		ids, err := loadFeed(m.ctx, m.client, m.cfg.Tabs[tabID])
+
+		return idsMsg{tabID: tabID, ids: ids, err: err}
+	}
+}
+
+// streamTab fetches the stories shown as skeleton rows in the given tab, the ones on the current page first,
+// sending each one as its own message as soon as it arrives.
+// It cancels the previous fetch of the tab, whose remaining stories are fetched again.
+func (m *model) streamTab(tabID int) tea.Cmd {
+	m.cancelFetch(tabID)
+
+	ranks := m.pendingRanks(tabID)
+	if len(ranks) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(ranks))
+	for n, rank := range ranks {
+		ids[n] = m.ids[tabID][rank]
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.fetches[tabID] = cancel
+
+	stories := make(chan storyMsg)
+
+	return func() tea.Msg {
+		go func() {
+			defer close(stories)
+
+			utils.StreamStories(ctx, m.client, [][]int{ids}, 0, 0, len(ids), func(n int, story list.Item) {
+				// Stories that failed because the fetch was canceled are left pending.
+				if ctx.Err() != nil {
+					return
+				}
+
+				select {
+				case stories <- storyMsg{tabID: tabID, rank: ranks[n], id: ids[n], item: story, stories: stories}:
+				case <-ctx.Done():
+				}
+			})
+		}()
+
+		return m.waitForStory(stories)()
+	}
+}
+
+// cancelFetch cancels the fetch of the given tab, if any.
+func (m *model) cancelFetch(tabID int) {
+	if m.fetches[tabID] != nil {
+		m.fetches[tabID]()
+		m.fetches[tabID] = nil
+	}
+}
+
+// waitForStory waits for the next story sent by streamTab.
+func (m model) waitForStory(stories <-chan storyMsg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-stories
+		if !ok {
+			return nil
+		}
+
+		return msg
+	}
+}
+
+// matches reports whether the title of the given story matches the filter declared for the tab.
+func (m model) matches(tabID int, story list.Item) bool {
+	filter := strings.ToLower(m.cfg.Tabs[tabID].Filter)
+
+	return filter == "" || strings.Contains(strings.ToLower(story.FilterValue()), filter)
+}
+
+func (m model) fetchComments(story *item.Item) tea.Cmd {
+	return func() tea.Msg {
+		// Search results don't carry the IDs of their replies, so the story is fetched again.
+		root := story
+		if it, err := m.client.GetItem(m.ctx, story.ID); err == nil {
+			root = it
+		}
+
+		return commentsMsg{
+			story:    story,
+			comments: utils.FetchComments(m.ctx, m.client, root),
+		}
+	}
+}
+
+func (m model) fetchPoll(poll *item.Item) tea.Cmd {
+	return func() tea.Msg {
+		// Search results don't carry the IDs of their options, so the poll is fetched again.
+		root := poll
+		if it, err := m.client.GetItem(m.ctx, poll.ID); err == nil {
+			root = it
+		}
+
+		return pollMsg{
+			poll:    poll,
+			options: utils.FetchPollOptions(m.ctx, m.client, root),
+		}
+	}
+}
+
+func (m model) fetchUser(id string, perPage int) tea.Cmd {
+	return func() tea.Msg {
+		u, err := m.client.GetUser(m.ctx, id)
+		if err != nil {
+			return userMsg{id: id, err: err}
+		}
+
+		end := utils.Min(perPage, len(u.Submitted))
+
+		return userMsg{
+			id:    id,
+			user:  u,
+			items: utils.FetchStories(m.ctx, m.client, [][]int{u.Submitted}, 0, 0, end),
+		}
+	}
+}
+
+func (m model) nextSubmitted(u *user.User, start, end int) tea.Cmd {
+	return func() tea.Msg {
+		return submittedMsg{
+			id:    u.ID,
+			items: utils.FetchStories(m.ctx, m.client, [][]int{u.Submitted}, 0, start, end),
+		}
+	}
+}
+
+func (m model) runSearch(q search.Query) tea.Cmd {
+	return func() tea.Msg {
+		res, err := m.searcher.Search(m.ctx, q)
+
+		return searchMsg{query: q, result: res, err: err}
+	}
+}
+
+// waitForUpdate waits for the next update sent by the API watcher.
+func (m model) waitForUpdate() tea.Cmd {
 	return func() tea.Msg {
-		docH, docV := m.theme.Doc.GetFrameSize()
-		winH, _ := m.theme.Window.GetFrameSize()
-		contH, contV := m.theme.ListContent.GetFrameSize()
-		for i := range m.tabs {
-			m.TabContent[i].SetSize(
-				m.width-docH-winH-contH,
-				m.height-docV-contV,
-			)
-			m.visited[i] = map[int]bool{}
-			m.TabContent[i].SetItems(
-				utils.FetchStories(m.ctx, m.client, m.ids, m.cfg.Workers, i, 0, m.TabContent[i].Paginator.PerPage),
-			)
+		u, ok := <-m.updates
+		if !ok {
+			return nil
 		}
 
-		return initMsg{}
+		return updateMsg{u}
 	}
 }
 
-func (m model) next(tabID, perPage, page int) tea.Cmd {
+// waitForRevalidation waits for expired items to be fetched again,
+// gathering the ones that are already available.
+func (m model) waitForRevalidation() tea.Cmd {
 	return func() tea.Msg {
-		n := next{}
-		n.items = utils.FetchStories(m.ctx, m.client, m.ids, m.cfg.Workers, tabID, perPage*page, perPage*page+perPage)
+		revalidated := m.client.Revalidated()
+
+		it, ok := <-revalidated
+		if !ok {
+			return nil
+		}
+
+		items := []*item.Item{it}
+		for {
+			select {
+			case it, ok = <-revalidated:
+				if !ok {
+					return revalidatedMsg{items: items}
+				}
+
+				items = append(items, it)
+			default:
+				return revalidatedMsg{items: items}
+			}
+		}
+	}
+}
+
+// waitForFeed waits for an expired feed to be fetched again, or for a streamed feed to change.
+func (m model) waitForFeed() tea.Cmd {
+	return func() tea.Msg {
+		var (
+			f  hn.Feed
+			ok bool
+		)
+
+		select {
+		case f, ok = <-m.client.RevalidatedFeeds():
+		case f, ok = <-m.feeds:
+		}
+
+		if !ok {
+			return nil
+		}
+
+		return feedMsg{f}
+	}
+}
+
+// refreshItems fetches the given items again, skipping the ones that could not be fetched.
+func (m model) refreshItems(ids []int) tea.Cmd {
+	return func() tea.Msg {
+		fetched, errs := m.client.GetItemsByIDs(m.ctx, ids)
+
+		items := make([]*item.Item, 0, len(fetched))
+		for n, it := range fetched {
+			if errs[n] == nil {
+				items = append(items, it)
+			}
+		}
 
-		return n
+		return refreshMsg{items: items}
 	}
 }
@@ -0,0 +1,285 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/KarolosLykos/hackertea/internal/item"
+	"github.com/KarolosLykos/hackertea/internal/tui/keys"
+	"github.com/KarolosLykos/hackertea/internal/tui/theme"
+	"github.com/KarolosLykos/hackertea/internal/utils"
+)
+
+// commentsView holds the state of the comments screen of a story.
+type commentsView struct {
+	story    *item.Item
+	comments []*item.Comment
+	rows     []*item.Comment
+	offsets  []int
+	blocks   []string
+	cache    map[blockKey]string
+	cursor   int
+	viewport viewport.Model
+	help     help.Model
+	keys     help.KeyMap
+}
+
+// blockKey identifies the rendering of a comment while it is not selected.
+type blockKey struct {
+	id        int
+	width     int
+	collapsed bool
+}
+
+// newCommentsView returns an empty comments screen for the given story.
+func newCommentsView(story *item.Item, width, height int) commentsView {
+	c := commentsView{
+		story:    story,
+		viewport: viewport.New(0, 0),
+		help:     help.New(),
+		keys:     keys.NewCommentsKeyMap(),
+	}
+
+	c.setSize(width, height)
+
+	return c
+}
+
+// setSize resizes the screen, keeping room for the story header and the help line.
+func (c *commentsView) setSize(width, height int) {
+	c.viewport.Width = width
+	c.viewport.Height = utils.Max(height-4, 1)
+	c.help.Width = width
+}
+
+// setComments replaces the discussion tree shown on the screen.
+func (c *commentsView) setComments(comments []*item.Comment, th *theme.Theme) {
+	c.comments = comments
+	c.rows = nil
+	c.cache = map[blockKey]string{}
+	c.cursor = 0
+	c.render(th)
+	c.viewport.GotoTop()
+}
+
+// moveCursor moves the selected comment by delta rows and scrolls it into view.
+func (c *commentsView) moveCursor(delta int, th *theme.Theme) {
+	if len(c.rows) == 0 {
+		return
+	}
+
+	c.cursor = utils.Max(0, utils.Min(c.cursor+delta, len(c.rows)-1))
+	c.setContent(th)
+}
+
+// selected returns the comment under the cursor, or nil if there are no comments.
+func (c commentsView) selected() *item.Comment {
+	if c.cursor >= len(c.rows) {
+		return nil
+	}
+
+	return c.rows[c.cursor]
+}
+
+// toggle collapses or expands the replies of the selected comment.
+func (c *commentsView) toggle(th *theme.Theme) {
+	if len(c.rows) == 0 {
+		return
+	}
+
+	selected := c.rows[c.cursor]
+	if len(selected.Children) == 0 {
+		return
+	}
+
+	selected.Collapsed = !selected.Collapsed
+
+	c.render(th)
+}
+
+// toggleSiblings collapses every comment at the level of the selected one,
+// or expands them all if they are already collapsed.
+func (c *commentsView) toggleSiblings(th *theme.Theme) {
+	if len(c.rows) == 0 {
+		return
+	}
+
+	selected := c.rows[c.cursor]
+	siblings := item.Siblings(c.comments, selected)
+
+	collapse := false
+	for _, sibling := range siblings {
+		if !sibling.Collapsed && len(sibling.Children) > 0 {
+			collapse = true
+			break
+		}
+	}
+
+	for _, sibling := range siblings {
+		sibling.Collapsed = collapse && len(sibling.Children) > 0
+	}
+
+	c.render(th)
+}
+
+// render flattens the discussion tree and writes it to the viewport,
+// keeping the cursor on the selected comment.
+func (c *commentsView) render(th *theme.Theme) {
+	var selected *item.Comment
+	if c.cursor < len(c.rows) {
+		selected = c.rows[c.cursor]
+	}
+
+	c.rows = nil
+	flatten(c.comments, &c.rows)
+
+	for i, row := range c.rows {
+		if row == selected {
+			c.cursor = i
+			break
+		}
+	}
+
+	c.offsets = make([]int, len(c.rows))
+	c.blocks = make([]string, len(c.rows))
+	lines := 0
+
+	for i, comment := range c.rows {
+		c.offsets[i] = lines
+		c.blocks[i] = c.block(comment, th)
+
+		lines += lipgloss.Height(c.blocks[i]) + 1
+	}
+
+	c.setContent(th)
+}
+
+// block returns the rendering of the given comment while it is not selected,
+// rendering it only the first time it is shown at the current width.
+func (c *commentsView) block(comment *item.Comment, th *theme.Theme) string {
+	key := blockKey{id: comment.ID, width: c.viewport.Width, collapsed: comment.Collapsed}
+	if block, ok := c.cache[key]; ok {
+		return block
+	}
+
+	block := renderComment(comment, false, c.viewport.Width, th)
+	c.cache[key] = block
+
+	return block
+}
+
+// setContent writes the rendered comments to the viewport, restyling the selected one,
+// and scrolls it into view.
+func (c *commentsView) setContent(th *theme.Theme) {
+	doc := strings.Builder{}
+
+	for i, block := range c.blocks {
+		if i == c.cursor {
+			block = renderComment(c.rows[i], true, c.viewport.Width, th)
+		}
+
+		doc.WriteString(block)
+		doc.WriteString("\n\n")
+	}
+
+	if len(c.rows) == 0 {
+		doc.WriteString(th.NormalDesc.Render("No comments yet."))
+	}
+
+	c.viewport.SetContent(doc.String())
+	c.scrollToCursor()
+}
+
+// scrollToCursor scrolls the viewport so the selected comment is visible.
+func (c *commentsView) scrollToCursor() {
+	if len(c.rows) == 0 {
+		return
+	}
+
+	start := c.offsets[c.cursor]
+	end := c.viewport.TotalLineCount()
+	if c.cursor < len(c.rows)-1 {
+		end = c.offsets[c.cursor+1] - 1
+	}
+
+	switch {
+	case start < c.viewport.YOffset:
+		c.viewport.SetYOffset(start)
+	case end > c.viewport.YOffset+c.viewport.Height:
+		c.viewport.SetYOffset(utils.Min(start, end-c.viewport.Height))
+	}
+}
+
+func (c commentsView) view(th *theme.Theme) string {
+	header := lipgloss.JoinVertical(
+		lipgloss.Left,
+		th.NormalTitle.Render(c.story.Title()),
+		th.NormalDesc.Render(c.story.Description()),
+	)
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		"",
+		c.viewport.View(),
+		c.help.View(c.keys),
+	)
+}
+
+// flatten appends the comments of the tree to rows in display order,
+// skipping the replies of collapsed comments.
+func flatten(comments []*item.Comment, rows *[]*item.Comment) {
+	for _, comment := range comments {
+		*rows = append(*rows, comment)
+		if !comment.Collapsed {
+			flatten(comment.Children, rows)
+		}
+	}
+}
+
+// renderComment renders a single comment indented by its depth in the thread.
+func renderComment(c *item.Comment, selected bool, width int, th *theme.Theme) string {
+	indent := c.Depth * 2
+
+	header := fmt.Sprintf("%s %s ago", c.By, c.Ago())
+	text := item.HTMLToText(c.Text)
+
+	switch {
+	case c.Deleted:
+		header, text = "[deleted]", ""
+	case c.Dead:
+		header, text = "[dead]", ""
+	case c.Timestamp == 0:
+		// Placeholders for comments that could not be fetched have no author nor age.
+		header = ""
+	}
+
+	if c.Collapsed {
+		header = fmt.Sprintf("%s [+%d]", header, c.Count())
+		text = ""
+	}
+
+	headerStyle, textStyle := th.NormalDesc, th.NormalTitle
+	if selected {
+		headerStyle, textStyle = th.SelectedDesc, th.SelectedTitle
+	}
+
+	textWidth := utils.Max(width-indent-1, 10)
+
+	var lines []string
+	if header != "" {
+		lines = append(lines, headerStyle.Copy().Width(textWidth).Render(header))
+	}
+
+	if text != "" {
+		lines = append(lines, textStyle.Copy().Width(textWidth).Render(text))
+	}
+
+	block := lipgloss.JoinVertical(lipgloss.Left, lines...)
+
+	return lipgloss.NewStyle().PaddingLeft(indent).Render(block)
+}
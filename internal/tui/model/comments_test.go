@@ -0,0 +1,55 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/hackertea/internal/item"
+	"github.com/KarolosLykos/hackertea/internal/tui/theme"
+)
+
+func TestCommentsView_Cache(t *testing.T) {
+	th, err := theme.NewTheme()
+	require.NoError(t, err)
+
+	reply := &item.Comment{Item: &item.Item{ID: 3, By: "b", Text: "reply", Timestamp: 1}, Depth: 1}
+	comments := []*item.Comment{
+		{Item: &item.Item{ID: 2, By: "a", Text: "first", Timestamp: 1}, Children: []*item.Comment{reply}},
+		{Item: &item.Item{ID: 4, By: "c", Text: "second", Timestamp: 1}},
+	}
+
+	c := newCommentsView(&item.Item{ID: 1}, 40, 30)
+	c.setComments(comments, th)
+	top := c.viewport.View()
+
+	assert.Len(t, c.cache, 3)
+
+	// Moving the cursor only restyles the selected comment.
+	c.moveCursor(1, th)
+	assert.Len(t, c.cache, 3)
+	assert.NotEqual(t, top, c.viewport.View())
+
+	c.moveCursor(-1, th)
+	assert.Equal(t, top, c.viewport.View())
+
+	// Collapsing a comment renders it again, and hides its replies.
+	c.toggle(th)
+	assert.Len(t, c.cache, 4)
+	assert.Len(t, c.rows, 2)
+	assert.Contains(t, c.viewport.View(), "[+1]")
+}
+
+func TestRenderComment_Placeholder(t *testing.T) {
+	th, err := theme.NewTheme()
+	require.NoError(t, err)
+
+	placeholder := &item.Comment{Item: &item.Item{ID: 2, Text: "Item not found"}}
+
+	rendered := renderComment(placeholder, false, 40, th)
+	assert.Contains(t, rendered, "Item not found")
+	assert.NotContains(t, rendered, "ago")
+	assert.Equal(t, 1, lipgloss.Height(rendered))
+}
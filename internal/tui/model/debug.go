@@ -0,0 +1,97 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/KarolosLykos/hackertea/internal/cache"
+	"github.com/KarolosLykos/hackertea/internal/client"
+	"github.com/KarolosLykos/hackertea/internal/tui/theme"
+	"github.com/KarolosLykos/hackertea/internal/utils"
+)
+
+// debugInterval is how often the debug overlay is refreshed.
+const debugInterval = time.Second
+
+// debugTick schedules the next refresh of the debug overlay.
+func debugTick() tea.Cmd {
+	return tea.Tick(debugInterval, func(time.Time) tea.Msg {
+		return debugTickMsg{}
+	})
+}
+
+// debugView renders the statistics of the item cache and of the HTTP client.
+func debugView(th *theme.Theme, workers int, cs cache.Stats, hasCache bool, rs client.Stats) string {
+	lines := []string{th.NormalTitle.Render("Debug"), ""}
+
+	if hasCache {
+		hitRate := 0.0
+		if lookups := cs.Hits + cs.Misses; lookups > 0 {
+			hitRate = 100 * float64(cs.Hits) / float64(lookups)
+		}
+
+		lines = append(lines,
+			fmt.Sprintf("cache     %d entries, %s", cs.Entries, formatBytes(cs.Bytes)),
+			fmt.Sprintf("hits      %d (%.1f%%)", cs.Hits, hitRate),
+			fmt.Sprintf("misses    %d", cs.Misses),
+			fmt.Sprintf("evictions %d", cs.Evictions),
+			"",
+		)
+	}
+
+	lines = append(lines,
+		fmt.Sprintf("workers   %d", workers),
+		fmt.Sprintf("requests  %d (%d failed)", rs.Requests, rs.Failures),
+		fmt.Sprintf("in flight %d", rs.InFlight),
+		fmt.Sprintf("fetched   %s", formatBytes(rs.Bytes)),
+		fmt.Sprintf("latency   p50 %s", rs.P50.Round(time.Millisecond)),
+		fmt.Sprintf("          p90 %s", rs.P90.Round(time.Millisecond)),
+		fmt.Sprintf("          p99 %s", rs.P99.Round(time.Millisecond)),
+	)
+
+	for i := 2; i < len(lines); i++ {
+		lines[i] = th.NormalDesc.Copy().UnsetPadding().Render(lines[i])
+	}
+
+	return th.Debug.Render(strings.Join(lines, "\n"))
+}
+
+// overlay draws the panel over the top right corner of the background, starting at the given line.
+func overlay(background, panel string, line int) string {
+	lines := strings.Split(background, "\n")
+	width := lipgloss.Width(background)
+
+	for i, p := range strings.Split(panel, "\n") {
+		n := line + i
+		if n >= len(lines) {
+			break
+		}
+
+		left := ansi.Truncate(lines[n], utils.Max(width-lipgloss.Width(p), 0), "")
+		pad := utils.Max(width-lipgloss.Width(p)-lipgloss.Width(left), 0)
+		lines[n] = left + strings.Repeat(" ", pad) + p
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// formatBytes returns the given size in a human readable form.
+func formatBytes(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -2,10 +2,75 @@ package model
 
 import (
 	"github.com/charmbracelet/bubbles/list"
+
+	"github.com/KarolosLykos/hackertea/internal/hn"
+	"github.com/KarolosLykos/hackertea/internal/item"
+	"github.com/KarolosLykos/hackertea/internal/search"
+	"github.com/KarolosLykos/hackertea/internal/user"
 )
 
-type initMsg struct{}
+// idsMsg carries the story IDs of a tab, or the reason they could not be fetched.
+type idsMsg struct {
+	tabID int
+	ids   []int
+	err   error
+}
+
+// storyMsg carries a story of a tab as soon as it arrives, along with its rank in the tab,
+// the ID it was fetched for and the channel the rest of the page is sent on.
+type storyMsg struct {
+	tabID   int
+	rank    int
+	id      int
+	item    list.Item
+	stories <-chan storyMsg
+}
+
+type commentsMsg struct {
+	story    *item.Item
+	comments []*item.Comment
+}
 
-type next struct {
+type userMsg struct {
+	id    string
+	user  *user.User
 	items []list.Item
+	err   error
+}
+
+type submittedMsg struct {
+	id    string
+	items []list.Item
+}
+
+type pollMsg struct {
+	poll    *item.Item
+	options []*item.Item
 }
+
+type updateMsg struct {
+	hn.Update
+}
+
+type refreshMsg struct {
+	items []*item.Item
+}
+
+// revalidatedMsg carries expired items that were fetched again in the background.
+type revalidatedMsg struct {
+	items []*item.Item
+}
+
+// feedMsg carries a feed that was fetched again in the background, or that changed on its stream.
+type feedMsg struct {
+	hn.Feed
+}
+
+type searchMsg struct {
+	query  search.Query
+	result *search.Result
+	err    error
+}
+
+// debugTickMsg refreshes the debug overlay.
+type debugTickMsg struct{}
@@ -2,23 +2,37 @@ package model
 
 import (
 	"context"
+	"fmt"
 	"runtime"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/KarolosLykos/hackertea/internal/client"
 	"github.com/KarolosLykos/hackertea/internal/config"
 	"github.com/KarolosLykos/hackertea/internal/constants"
 	"github.com/KarolosLykos/hackertea/internal/hn"
 	"github.com/KarolosLykos/hackertea/internal/item"
+	"github.com/KarolosLykos/hackertea/internal/search"
 	"github.com/KarolosLykos/hackertea/internal/tui/keys"
 	"github.com/KarolosLykos/hackertea/internal/tui/theme"
 	"github.com/KarolosLykos/hackertea/internal/utils"
 )
 
+type screen int
+
+const (
+	listScreen screen = iota
+	commentsScreen
+	profileScreen
+	pollScreen
+)
+
 type model struct {
 	ctx           context.Context
 	cancel        context.CancelFunc
@@ -29,21 +43,36 @@ type model struct {
 	activeTab     int
 	loading       bool
 	client        *hn.HN
+	searcher      search.Service
+	metrics       *client.Metrics
+	debug         bool
 	spinner       spinner.Model
 	ids           [][]int
-	visited       []map[int]bool
+	status        []tabStatus
+	fetches       []context.CancelFunc
+	loaded        []int
+	filtered      []map[int]bool
+	screen        screen
+	history       []screen
+	comments      commentsView
+	profile       profileView
+	poll          pollView
+	search        searchView
+	updates       <-chan hn.Update
+	feeds         <-chan hn.Feed
+	updated       map[int]bool
 	width, height int
 }
 
-func New(ctx context.Context, client *hn.HN) (*model, error) {
+func New(
+	ctx context.Context,
+	cfg *config.Config,
+	client *hn.HN,
+	searcher search.Service,
+	metrics *client.Metrics,
+) (*model, error) {
 	newCtx, cancel := context.WithCancel(ctx)
 
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		cancel()
-		return nil, err
-	}
-
 	th, err := theme.NewTheme()
 	if err != nil {
 		cancel()
@@ -53,43 +82,41 @@ func New(ctx context.Context, client *hn.HN) (*model, error) {
 	s := spinner.New()
 	s.Spinner = spinner.Points
 
-	topStories, err := client.GetItems(newCtx, constants.Items.TopItems)
-	if err != nil {
-		cancel()
-		return nil, err
-	}
-
-	bestStories, err := client.GetItems(newCtx, constants.Items.BestItems)
-	if err != nil {
-		cancel()
-		return nil, err
+	tabs := make([]string, len(cfg.Tabs), len(cfg.Tabs)+1)
+	for i, t := range cfg.Tabs {
+		tabs[i] = t.Name
 	}
 
-	newStories, err := client.GetItems(newCtx, constants.Items.NewItems)
-	if err != nil {
-		cancel()
-		return nil, err
-	}
-
-	askStories, err := client.GetItems(newCtx, constants.Items.AskItems)
-	if err != nil {
-		cancel()
-		return nil, err
-	}
+	// The search tab always comes last.
+	tabs = append(tabs, constants.TabSearch)
 
 	m := &model{
-		cfg:     cfg,
-		ctx:     newCtx,
-		cancel:  cancel,
-		theme:   th,
-		ids:     [][]int{topStories, newStories, bestStories, askStories},
-		client:  client,
-		spinner: s,
-		visited: make([]map[int]bool, 4),
-		tabs:    []string{constants.TabTop, constants.TabNew, constants.TabBest, constants.TabAsk},
+		cfg:      cfg,
+		ctx:      newCtx,
+		cancel:   cancel,
+		theme:    th,
+		ids:      make([][]int, len(cfg.Tabs)),
+		status:   make([]tabStatus, len(cfg.Tabs)),
+		fetches:  make([]context.CancelFunc, len(cfg.Tabs)),
+		updated:  map[int]bool{},
+		client:   client,
+		searcher: searcher,
+		metrics:  metrics,
+		spinner:  s,
+		tabs:     tabs,
 	}
 
-	m.TabContent = m.createTabContent(4)
+	m.updates = client.Watch(newCtx, constants.RefreshInterval)
+	m.feeds = client.WatchFeeds(newCtx, constants.RefreshInterval, feedTypes(cfg.Tabs)...)
+	m.loaded = make([]int, len(cfg.Tabs))
+	m.filtered = make([]map[int]bool, len(cfg.Tabs))
+	for i := range m.filtered {
+		m.filtered[i] = map[int]bool{}
+	}
+	m.TabContent = m.createTabContent(len(cfg.Tabs))
+	m.search = newSearchView(m.newList())
+	// The profile list is resized along with the window before any profile is opened.
+	m.profile = profileView{list: m.newList()}
 
 	listKeys := keys.NewListKeyMap()
 
@@ -98,52 +125,224 @@ func New(ctx context.Context, client *hn.HN) (*model, error) {
 		m.TabContent[i].AdditionalFullHelpKeys = listKeys.KeyBindings()
 	}
 
+	searchKeys := keys.NewSearchKeyMap()
+	m.search.list.AdditionalShortHelpKeys = searchKeys.KeyBindings()
+	m.search.list.AdditionalFullHelpKeys = searchKeys.KeyBindings()
+
 	return m, nil
 }
 
 func (m model) Init() tea.Cmd {
-	return nil
+	// The story IDs of every tab are fetched at the same time; their stories only once shown.
+	cmds := []tea.Cmd{m.spinner.Tick, m.waitForUpdate(), m.waitForRevalidation(), m.waitForFeed()}
+	for i := range m.cfg.Tabs {
+		cmds = append(cmds, m.fetchFeed(i))
+	}
+
+	return tea.Batch(cmds...)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
-	case next:
+	case idsMsg:
+		if msg.err != nil {
+			m.status[msg.tabID].err = msg.err
+			return m, nil
+		}
+
+		m.ids[msg.tabID] = msg.ids
+		m.status[msg.tabID].loaded = true
+
+		if msg.tabID != m.activeTab {
+			return m, nil
+		}
+
+		return m, m.startTab(msg.tabID)
+	case storyMsg:
+		cmd = m.setStory(msg)
+
+		// The page following the last one is fetched as soon as it is done, so that scrolling on doesn't wait.
+		return m, tea.Batch(m.waitForStory(msg.stories), cmd, m.loadMore())
+	case searchMsg:
+		m.loading = false
+		m.search.err = msg.err
+		if msg.err != nil {
+			return m, nil
+		}
+
+		items := make([]list.Item, 0, len(m.search.list.Items())+len(msg.result.Items))
+		if msg.query.Page > 0 {
+			items = append(items, m.search.list.Items()...)
+		}
+
+		for _, it := range msg.result.Items {
+			items = append(items, it)
+		}
+
+		m.search.query, m.search.result = msg.query, msg.result
+		cmd = m.search.list.SetItems(items)
+		if msg.query.Page > 0 {
+			m.search.list.Paginator.NextPage()
+		} else {
+			m.search.list.ResetSelected()
+		}
+
+		return m, cmd
+	case commentsMsg:
+		if m.screen != commentsScreen || m.comments.story.ID != msg.story.ID {
+			return m, nil
+		}
+
 		m.loading = false
-		items := append(m.TabContent[m.activeTab].Items(), msg.items...)
-		m.TabContent[m.activeTab].SetItems(items)
-		m.visited[m.activeTab][m.TabContent[m.activeTab].Paginator.Page] = true
-		m.TabContent[m.activeTab].Paginator.NextPage()
+		m.comments.setComments(msg.comments, m.theme)
+
+		return m, nil
+	case updateMsg:
+		constants.CurrentTime = time.Now()
+		for _, id := range msg.Items {
+			m.updated[id] = true
+		}
+
+		cmds := []tea.Cmd{m.waitForUpdate()}
+		if ids := m.loadedItems(msg.Items); len(ids) > 0 {
+			cmds = append(cmds, m.refreshItems(ids))
+		}
+
+		return m, tea.Batch(cmds...)
+	case refreshMsg:
+		return m, m.replaceItems(msg.items)
+	case revalidatedMsg:
+		return m, tea.Batch(m.waitForRevalidation(), m.replaceItems(msg.items))
+	case feedMsg:
+		cmds := []tea.Cmd{m.waitForFeed()}
+		for i, t := range m.cfg.Tabs {
+			if t.Source != string(msg.Type) || !m.status[i].loaded || slices.Equal(m.ids[i], msg.IDs) {
+				continue
+			}
+
+			// The stories in flight were requested for the previous ranks.
+			m.cancelFetch(i)
+			m.ids[i] = msg.IDs
+			// Tabs that were not loaded yet will use the new IDs.
+			if m.status[i].ready {
+				cmds = append(cmds, m.reloadTab(i))
+			}
+		}
+
+		return m, tea.Batch(cmds...)
+	case pollMsg:
+		if m.screen != pollScreen || m.poll.poll.ID != msg.poll.ID {
+			return m, nil
+		}
+
+		m.loading = false
+		m.poll.setOptions(msg.options, m.theme)
+
+		return m, nil
+	case userMsg:
+		if m.screen != profileScreen || m.profile.id != msg.id {
+			return m, nil
+		}
+
+		m.loading = false
+		m.profile.user, m.profile.err = msg.user, msg.err
+		m.profile.list.SetItems(msg.items)
+
+		return m, nil
+	case submittedMsg:
+		if m.screen != profileScreen || m.profile.id != msg.id {
+			return m, nil
+		}
+
+		m.loading = false
+		m.profile.list.SetItems(append(m.profile.list.Items(), msg.items...))
+		m.profile.list.Paginator.NextPage()
+
+		return m, nil
+	case debugTickMsg:
+		if !m.debug {
+			return m, nil
+		}
+
+		return m, debugTick()
 	case tea.KeyMsg:
+		if msg.String() == "ctrl+d" {
+			m.debug = !m.debug
+			if m.debug {
+				return m, debugTick()
+			}
+
+			return m, nil
+		}
+
+		switch m.screen {
+		case commentsScreen:
+			return m.updateComments(msg)
+		case profileScreen:
+			return m.updateProfile(msg)
+		case pollScreen:
+			return m.updatePoll(msg)
+		}
+
+		if m.onSearchTab() && m.search.input.Focused() {
+			return m.updateSearchInput(msg)
+		}
+
 		// Don't match any of the keys below if we're actively filtering.
-		if m.TabContent[m.activeTab].FilterState() == list.Filtering {
+		if m.activeList().FilterState() == list.Filtering {
 			break
 		}
 		switch msg.String() {
 		case "ctrl+c", "q":
-			return m, tea.Quit
+			return m, m.quit()
 		case tea.KeyEnter.String():
-			if v, ok := m.TabContent[m.activeTab].SelectedItem().(*item.Item); ok {
+			if v, ok := m.activeList().SelectedItem().(*item.Item); ok {
+				if v.Type == "poll" {
+					return m.openPoll(v)
+				}
+
 				if err := utils.Open(v.URL, runtime.GOOS); err != nil {
 					return m, nil
 				}
 				v.Visited = true
 			}
+		case "c":
+			if v, ok := m.activeList().SelectedItem().(*item.Item); ok {
+				return m.openComments(v)
+			}
+		case "u":
+			if v, ok := m.activeList().SelectedItem().(*item.Item); ok && v.By != "" {
+				return m.openProfile(v.By)
+			}
+		case "s":
+			if m.onSearchTab() {
+				return m, m.search.input.Focus()
+			}
 		case "n":
-			if !m.visited[m.activeTab][m.TabContent[m.activeTab].Paginator.Page] {
-				m.loading = true
-				return m, m.next(m.activeTab, m.TabContent[m.activeTab].Paginator.PerPage, m.TabContent[m.activeTab].Paginator.Page+1)
+			if m.onSearchTab() {
+				if m.search.hasMore() && m.search.list.Paginator.OnLastPage() {
+					q := m.search.query
+					q.Page++
+					m.loading = true
+
+					return m, tea.Batch(m.spinner.Tick, m.runSearch(q))
+				}
+
+			}
+		case "r":
+			if !m.onSearchTab() && m.status[m.activeTab].err != nil {
+				m.status[m.activeTab] = tabStatus{}
+
+				return m, tea.Batch(m.spinner.Tick, m.fetchFeed(m.activeTab))
 			}
 		case "t", "tab":
-			m.activeTab = utils.Min(m.activeTab+1, len(m.tabs)-1)
+			return m, m.switchTab(utils.Min(m.activeTab+1, len(m.tabs)-1))
 		case "T", "shift+tab":
-			m.activeTab = utils.Max(m.activeTab-1, 0)
+			return m, m.switchTab(utils.Max(m.activeTab-1, 0))
 		}
-	case initMsg:
-		m.loading = false
-
 	case spinner.TickMsg:
-		if !m.loading {
+		if !m.loading && !m.tabPending() && !m.loadingMore() {
 			return m, nil
 		}
 
@@ -151,16 +350,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	case tea.WindowSizeMsg:
 		m.width, m.height = msg.Width, msg.Height
-		m.loading = true
-		return m, tea.Batch(
-			m.spinner.Tick,
-			m.initCmd(),
-		)
+		width, height := m.contentSize()
+		m.comments.setSize(width, height)
+		m.profile.setSize(width, height)
+		m.poll.setSize(width, height)
+		m.poll.render(m.theme)
+		m.search.setSize(width, height)
+		for i := range m.TabContent {
+			m.TabContent[i].SetSize(width, utils.Max(height-loadingIndicatorHeight, 1))
+		}
+
+		if m.onSearchTab() {
+			return m, nil
+		}
+
+		return m, m.startTab(m.activeTab)
 	}
 
-	m.TabContent[m.activeTab], cmd = m.TabContent[m.activeTab].Update(msg)
+	l := m.activeList()
+	*l, cmd = l.Update(msg)
 
-	return m, cmd
+	return m, tea.Batch(cmd, m.loadMore())
 }
 
 func (m model) View() string {
@@ -171,7 +381,12 @@ func (m model) View() string {
 	docFrameSize := m.theme.Doc.GetHorizontalFrameSize()
 	var renderedTabs []string
 
-	renderedTabs = append(renderedTabs, m.theme.TitleTab.Render("HackerTea"))
+	title := "HackerTea"
+	if n := m.updatedStories(); n > 0 {
+		title = fmt.Sprintf("%s +%d", title, n)
+	}
+
+	renderedTabs = append(renderedTabs, m.theme.TitleTab.Render(title))
 
 	for i, t := range m.tabs {
 		var style lipgloss.Style
@@ -209,16 +424,537 @@ func (m model) View() string {
 
 	if m.loading {
 		doc.WriteString(m.theme.Window.Render(m.spinner.View()))
+	} else if m.screen == listScreen && m.onSearchTab() {
+		doc.WriteString(m.theme.Window.Render(m.search.view(m.theme)))
+	} else if m.screen == commentsScreen {
+		doc.WriteString(m.theme.Window.Render(m.comments.view(m.theme)))
+	} else if m.screen == profileScreen {
+		doc.WriteString(m.theme.Window.Render(m.profile.view(m.theme)))
+	} else if m.screen == pollScreen {
+		doc.WriteString(m.theme.Window.Render(m.poll.view()))
+	} else if s := m.status[m.activeTab]; s.err != nil {
+		doc.WriteString(m.theme.Window.Render(s.view(m.theme, m.tabs[m.activeTab])))
+	} else if s.pending() {
+		doc.WriteString(m.theme.Window.Render(m.spinner.View()))
 	} else {
-		doc.WriteString(m.theme.Window.Render(m.TabContent[m.activeTab].View()))
+		doc.WriteString(m.theme.Window.Render(m.tabView()))
+	}
+
+	if !m.debug {
+		return m.theme.Doc.Render(doc.String())
+	}
+
+	cacheStats, hasCache := m.client.CacheStats()
+	panel := debugView(m.theme, m.cfg.Workers, cacheStats, hasCache, m.metrics.Stats())
+
+	// The panel is drawn below the tabs.
+	return m.theme.Doc.Render(overlay(doc.String(), panel, lipgloss.Height(row)+1))
+}
+
+// updateComments handles the key presses while the comments screen is shown.
+func (m model) updateComments(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, m.quit()
+	case "esc", "backspace":
+		m.back()
+	case "u":
+		if c := m.comments.selected(); c != nil && c.By != "" {
+			return m.openProfile(c.By)
+		}
+	case "up", "k":
+		m.comments.moveCursor(-1, m.theme)
+	case "down", "j":
+		m.comments.moveCursor(1, m.theme)
+	case "home", "g":
+		m.comments.moveCursor(-len(m.comments.rows), m.theme)
+	case "end", "G":
+		m.comments.moveCursor(len(m.comments.rows), m.theme)
+	case " ", "enter":
+		m.comments.toggle(m.theme)
+	case "C":
+		m.comments.toggleSiblings(m.theme)
+	}
+
+	return m, nil
+}
+
+// updateProfile handles the key presses while the profile screen is shown.
+func (m model) updateProfile(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	// Let the list handle the keys while the submissions are being filtered.
+	if m.profile.list.FilterState() == list.Filtering {
+		m.profile.list, cmd = m.profile.list.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, m.quit()
+	case "esc", "backspace":
+		m.back()
+		return m, nil
+	case tea.KeyEnter.String():
+		if v, ok := m.profile.list.SelectedItem().(*item.Item); ok {
+			if v.Type == "poll" {
+				return m.openPoll(v)
+			}
+
+			if err := utils.Open(v.URL, runtime.GOOS); err != nil {
+				return m, nil
+			}
+			v.Visited = true
+		}
+	case "c":
+		if v, ok := m.profile.list.SelectedItem().(*item.Item); ok {
+			return m.openComments(v)
+		}
+	case "n":
+		if m.profile.hasMore() && m.profile.list.Paginator.OnLastPage() {
+			start, end := m.profile.nextPage()
+			m.loading = true
+
+			return m, tea.Batch(m.spinner.Tick, m.nextSubmitted(m.profile.user, start, end))
+		}
+	}
+
+	m.profile.list, cmd = m.profile.list.Update(msg)
+
+	return m, cmd
+}
+
+// updateSearchInput handles the key presses while the search query is being typed.
+func (m model) updateSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "tab":
+		return m, m.switchTab(utils.Min(m.activeTab+1, len(m.tabs)-1))
+	case "shift+tab":
+		return m, m.switchTab(utils.Max(m.activeTab-1, 0))
+	case "esc":
+		m.search.input.Blur()
+	case tea.KeyEnter.String():
+		q, err := search.ParseQuery(m.search.input.Value())
+		if err != nil {
+			m.search.err = err
+			return m, nil
+		}
+
+		m.search.input.Blur()
+		m.loading = true
+
+		return m, tea.Batch(m.spinner.Tick, m.runSearch(q))
+	default:
+		m.search.input, cmd = m.search.input.Update(msg)
+	}
+
+	return m, cmd
+}
+
+// updatePoll handles the key presses while the poll screen is shown.
+func (m model) updatePoll(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, m.quit()
+	case "esc", "backspace":
+		m.back()
+	case "c":
+		return m.openComments(m.poll.poll)
+	case "u":
+		if m.poll.poll.By != "" {
+			return m.openProfile(m.poll.poll.By)
+		}
+	default:
+		var cmd tea.Cmd
+		m.poll.viewport, cmd = m.poll.viewport.Update(msg)
+
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// openPoll shows the results screen of the given poll and starts fetching its options.
+func (m model) openPoll(poll *item.Item) (tea.Model, tea.Cmd) {
+	width, height := m.contentSize()
+
+	m.open(pollScreen)
+	m.poll = newPollView(poll, width, height)
+	m.loading = true
+
+	return m, tea.Batch(m.spinner.Tick, m.fetchPoll(poll))
+}
+
+// openComments shows the comments screen of the given story and starts fetching its discussion.
+func (m model) openComments(story *item.Item) (tea.Model, tea.Cmd) {
+	width, height := m.contentSize()
+
+	m.open(commentsScreen)
+	m.comments = newCommentsView(story, width, height)
+	m.loading = true
+
+	return m, tea.Batch(m.spinner.Tick, m.fetchComments(story))
+}
+
+// openProfile shows the profile screen of the given user and starts fetching their submissions.
+func (m model) openProfile(id string) (tea.Model, tea.Cmd) {
+	width, height := m.contentSize()
+
+	l := m.newList()
+	l.AdditionalShortHelpKeys = keys.NewProfileKeyMap().KeyBindings()
+	l.AdditionalFullHelpKeys = keys.NewProfileKeyMap().KeyBindings()
+	l.DisableQuitKeybindings()
+
+	m.open(profileScreen)
+	m.profile = newProfileView(id, l, width, height)
+	m.loading = true
+
+	return m, tea.Batch(m.spinner.Tick, m.fetchUser(id, m.profile.list.Paginator.PerPage))
+}
+
+// open shows the given screen and remembers the current one, so that back can return to it.
+// Opening a screen that is already in the history drops everything shown after it.
+func (m *model) open(s screen) {
+	history := append(m.history, m.screen)
+	for i, h := range history {
+		if h == s {
+			history = history[:i]
+			break
+		}
+	}
+
+	m.history = history
+	m.screen = s
+}
+
+// back returns to the previously shown screen.
+func (m *model) back() {
+	m.loading = false
+
+	if len(m.history) == 0 {
+		m.screen = listScreen
+		return
+	}
+
+	m.screen = m.history[len(m.history)-1]
+	m.history = m.history[:len(m.history)-1]
+}
+
+// switchTab shows the given tab, loading its stories on first activation.
+// The stories still pending in the previous tab are no longer fetched, until it is shown again.
+func (m *model) switchTab(tabID int) tea.Cmd {
+	if tabID == m.activeTab {
+		return nil
+	}
+
+	if !m.onSearchTab() {
+		m.cancelFetch(m.activeTab)
+	}
+
+	m.activeTab = tabID
+	if m.onSearchTab() {
+		return nil
+	}
+
+	if m.tabPending() {
+		return tea.Batch(m.spinner.Tick, m.startTab(tabID))
+	}
+
+	return tea.Batch(m.spinner.Tick, m.streamTab(tabID))
+}
+
+// quit stops every fetch in flight and exits the program.
+func (m model) quit() tea.Cmd {
+	m.cancel()
+
+	return tea.Quit
+}
+
+// startTab fetches the first page of stories of the given tab, once its story IDs and the window size
+// are known, unless it was already requested.
+func (m *model) startTab(tabID int) tea.Cmd {
+	if m.width == 0 || !m.status[tabID].loaded || m.status[tabID].ready {
+		return nil
+	}
+
+	m.status[tabID].ready = true
+
+	l := &m.TabContent[tabID]
+	m.loaded[tabID] = utils.Min(l.Paginator.PerPage, len(m.ids[tabID]))
+
+	return tea.Batch(l.SetItems(m.skeletons(tabID, 0, m.loaded[tabID])), m.spinner.Tick, m.streamTab(tabID))
+}
+
+// next shows skeleton rows at the bottom of the given tab for the page of stories following the loaded ones,
+// and starts streaming the stories into them. The page starts right after the last loaded rank,
+// whatever the page size was when the previous ones were loaded.
+func (m *model) next(tabID int) tea.Cmd {
+	l := &m.TabContent[tabID]
+	start := m.loaded[tabID]
+	m.loaded[tabID] = utils.Min(start+l.Paginator.PerPage, len(m.ids[tabID]))
+
+	cmd := l.SetItems(append(l.Items(), m.skeletons(tabID, start, m.loaded[tabID])...))
+
+	return tea.Batch(cmd, m.spinner.Tick, m.streamTab(tabID))
+}
+
+// loadMore fetches the next page of stories of the active tab once its last page is shown,
+// unless stories are still pending or every story is loaded.
+func (m *model) loadMore() tea.Cmd {
+	if m.screen != listScreen || m.onSearchTab() || !m.status[m.activeTab].ready {
+		return nil
+	}
+
+	l := &m.TabContent[m.activeTab]
+	if !l.Paginator.OnLastPage() || l.FilterState() != list.Unfiltered || m.loadingMore() {
+		return nil
+	}
+
+	if m.loaded[m.activeTab] >= len(m.ids[m.activeTab]) {
+		return nil
+	}
+
+	return m.next(m.activeTab)
+}
+
+// loadingMore reports whether stories of the active tab are still being fetched.
+func (m model) loadingMore() bool {
+	return !m.onSearchTab() && m.status[m.activeTab].ready && len(m.pendingRanks(m.activeTab)) > 0
+}
+
+// skeletons returns the rows shown for the stories of the given tab between start and end until they arrive,
+// leaving out the stories already known not to match the filter of the tab.
+func (m model) skeletons(tabID, start, end int) []list.Item {
+	end = utils.Min(end, len(m.ids[tabID]))
+
+	rows := make([]list.Item, 0, utils.Max(end-start, 0))
+	for rank := start; rank < end; rank++ {
+		if !m.filtered[tabID][m.ids[tabID][rank]] {
+			rows = append(rows, skeleton{rank: rank})
+		}
+	}
+
+	return rows
+}
+
+// pendingRanks returns the ranks of the skeleton rows of the given tab, the ones on the current page first.
+func (m model) pendingRanks(tabID int) []int {
+	l := m.TabContent[tabID]
+	start, end := l.Paginator.GetSliceBounds(len(l.Items()))
+
+	var shown, others []int
+	for i, li := range l.Items() {
+		// Rows past the end of a feed that shrank are left out.
+		s, ok := li.(skeleton)
+		if !ok || s.rank >= len(m.ids[tabID]) {
+			continue
+		}
+
+		if i >= start && i < end {
+			shown = append(shown, s.rank)
+		} else {
+			others = append(others, s.rank)
+		}
+	}
+
+	return append(shown, others...)
+}
+
+// setStory swaps the given story into the skeleton row kept for its rank,
+// or drops the row if the story doesn't match the filter declared for the tab.
+// Stories fetched for a rank that the feed has since given to another story are dropped.
+func (m *model) setStory(msg storyMsg) tea.Cmd {
+	if ids := m.ids[msg.tabID]; msg.rank >= len(ids) || ids[msg.rank] != msg.id {
+		return nil
 	}
 
-	return m.theme.Doc.Render(doc.String())
+	l := &m.TabContent[msg.tabID]
+	for i, li := range l.Items() {
+		if s, ok := li.(skeleton); !ok || s.rank != msg.rank {
+			continue
+		}
+
+		if !m.matches(msg.tabID, msg.item) {
+			m.filtered[msg.tabID][msg.id] = true
+			l.RemoveItem(i)
+			return nil
+		}
+
+		return l.SetItem(i, msg.item)
+	}
+
+	return nil
+}
+
+// tabPending reports whether the active tab is a story tab still being loaded.
+func (m model) tabPending() bool {
+	return !m.onSearchTab() && m.status[m.activeTab].pending()
+}
+
+// onSearchTab reports whether the search tab is the active one.
+func (m model) onSearchTab() bool {
+	return m.activeTab == len(m.TabContent)
+}
+
+// activeList returns the list of the active tab.
+func (m *model) activeList() *list.Model {
+	if m.onSearchTab() {
+		return &m.search.list
+	}
+
+	return &m.TabContent[m.activeTab]
+}
+
+// lists returns every list of items currently held by the model.
+func (m *model) lists() []*list.Model {
+	lists := make([]*list.Model, 0, len(m.TabContent)+2)
+	for i := range m.TabContent {
+		lists = append(lists, &m.TabContent[i])
+	}
+
+	return append(lists, &m.profile.list, &m.search.list)
+}
+
+// updatedStories returns the number of stories of the active tab reported as changed since startup.
+// Comments and stories of other feeds are not counted.
+func (m model) updatedStories() int {
+	if m.onSearchTab() {
+		return 0
+	}
+
+	n := 0
+	for _, id := range m.ids[m.activeTab] {
+		if m.updated[id] {
+			n++
+		}
+	}
+
+	return n
+}
+
+// loadedItems returns the IDs among ids that are shown in any of the lists.
+func (m model) loadedItems(ids []int) []int {
+	changed := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		changed[id] = true
+	}
+
+	loaded := make([]int, 0)
+	for _, l := range m.lists() {
+		for _, li := range l.Items() {
+			if v, ok := li.(*item.Item); ok && changed[v.ID] {
+				loaded = append(loaded, v.ID)
+				delete(changed, v.ID)
+			}
+		}
+	}
+
+	return loaded
+}
+
+// replaceItems swaps the refreshed items into every list that shows them,
+// keeping their visited state.
+func (m *model) replaceItems(items []*item.Item) tea.Cmd {
+	refreshed := make(map[int]*item.Item, len(items))
+	for _, it := range items {
+		refreshed[it.ID] = it
+	}
+
+	var cmds []tea.Cmd
+	for _, l := range m.lists() {
+		for i, li := range l.Items() {
+			v, ok := li.(*item.Item)
+			if !ok {
+				continue
+			}
+
+			if it, ok := refreshed[v.ID]; ok && it != v {
+				it.Visited = v.Visited
+				cmds = append(cmds, l.SetItem(i, it))
+			}
+		}
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// reloadTab lays the stories loaded in the given tab out again after its feed changed.
+// The stories still in the feed keep their row, the others are shown as skeleton rows
+// and streamed again while the tab is shown, unless the filter of the tab already removed them.
+// The selected story stays selected.
+func (m *model) reloadTab(tabID int) tea.Cmd {
+	m.cancelFetch(tabID)
+
+	l := &m.TabContent[tabID]
+
+	shown := make(map[int]*item.Item, len(l.Items()))
+	for _, li := range l.Items() {
+		if v, ok := li.(*item.Item); ok && v.ID != 0 {
+			shown[v.ID] = v
+		}
+	}
+
+	selected := 0
+	if v, ok := l.SelectedItem().(*item.Item); ok {
+		selected = v.ID
+	}
+
+	m.loaded[tabID] = utils.Min(m.loaded[tabID], len(m.ids[tabID]))
+	index := l.Index()
+
+	items := make([]list.Item, 0, m.loaded[tabID])
+	for rank, id := range m.ids[tabID][:m.loaded[tabID]] {
+		v, ok := shown[id]
+		if !ok {
+			if !m.filtered[tabID][id] {
+				items = append(items, skeleton{rank: rank})
+			}
+
+			continue
+		}
+
+		if id == selected {
+			index = len(items)
+		}
+
+		items = append(items, v)
+	}
+
+	cmd := l.SetItems(items)
+	l.Select(utils.Max(utils.Min(index, len(items)-1), 0))
+
+	if tabID != m.activeTab {
+		return cmd
+	}
+
+	return tea.Batch(cmd, m.spinner.Tick, m.streamTab(tabID))
+}
+
+// contentSize returns the width and height available inside the window.
+func (m model) contentSize() (int, int) {
+	docH, docV := m.theme.Doc.GetFrameSize()
+	winH, _ := m.theme.Window.GetFrameSize()
+	contH, contV := m.theme.ListContent.GetFrameSize()
+
+	return m.width - docH - winH - contH, m.height - docV - contV
 }
 
 func (m model) createTabContent(tabs int) []list.Model {
 	tabContent := make([]list.Model, tabs)
 
+	for i := 0; i < tabs; i++ {
+		tabContent[i] = m.newList()
+	}
+
+	return tabContent
+}
+
+// newList returns an empty list of items styled with the current theme.
+func (m model) newList() list.Model {
 	delegate := list.NewDefaultDelegate()
 	delegate.Styles = list.DefaultItemStyles{
 		NormalTitle:   m.theme.NormalTitle,
@@ -230,13 +966,9 @@ func (m model) createTabContent(tabs int) []list.Model {
 		FilterMatch:   m.theme.FilterMatch,
 	}
 
-	for i := 0; i < tabs; i++ {
-		l := list.New(make([]list.Item, 0), delegate, 0, 0)
-		l.SetShowTitle(false)
-		l.SetShowStatusBar(false)
-
-		tabContent[i] = l
-	}
+	l := list.New(make([]list.Item, 0), delegate, 0, 0)
+	l.SetShowTitle(false)
+	l.SetShowStatusBar(false)
 
-	return tabContent
+	return l
 }
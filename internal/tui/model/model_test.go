@@ -0,0 +1,213 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/hackertea/internal/cache"
+	"github.com/KarolosLykos/hackertea/internal/client"
+	"github.com/KarolosLykos/hackertea/internal/config"
+	"github.com/KarolosLykos/hackertea/internal/constants"
+	"github.com/KarolosLykos/hackertea/internal/hn"
+	"github.com/KarolosLykos/hackertea/internal/item"
+	"github.com/KarolosLykos/hackertea/internal/mock/client"
+)
+
+// newTestModel returns a model over the given mocked HTTP client, with a single tab of top stories.
+func newTestModel(t *testing.T, c *mock_client.MockHttpClient) model {
+	t.Helper()
+
+	// The feeds of the tabs are streamed until the model is closed.
+	c.EXPECT().Stream(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(ctx context.Context, _ string, _ func(client.Event) error) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	)
+
+	cfg := &config.Config{Workers: 2, Tabs: []config.Feed{{Name: "Top", Source: "top"}}}
+
+	m, err := New(context.Background(), cfg, hn.New(c, cache.New()), nil, client.NewMetrics())
+	require.NoError(t, err)
+	t.Cleanup(m.cancel)
+
+	return *m
+}
+
+func TestModel_WindowSize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := newTestModel(t, mock_client.NewMockHttpClient(ctrl))
+
+	// Bubble Tea sends the window size at startup, before any screen is opened.
+	assert.NotPanics(t, func() {
+		updated, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+		_ = updated.View()
+	})
+}
+
+func TestModel_UpdatedStories(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := newTestModel(t, mock_client.NewMockHttpClient(ctrl))
+	m.ids[0] = []int{1, 2, 3}
+
+	// Comments and stories of other feeds are reported too.
+	updated, _ := m.Update(updateMsg{hn.Update{Updates: hn.Updates{Items: []int{2, 3, 42, 99}}}})
+	m = updated.(model)
+
+	assert.Equal(t, 2, m.updatedStories())
+	assert.Contains(t, m.View(), "HackerTea +2")
+
+	m.activeTab = len(m.TabContent)
+	assert.Equal(t, 0, m.updatedStories())
+}
+
+func TestFeedTypes(t *testing.T) {
+	tabs := []config.Feed{
+		{Name: "Top", Source: "top"},
+		{Name: "Mine", Source: "user", User: "pg"},
+		{Name: "Also top", Source: "top"},
+		{Name: "Ask", Source: "ask"},
+	}
+
+	assert.Equal(t, []constants.ItemType{constants.Items.TopItems, constants.Items.AskItems}, feedTypes(tabs))
+}
+
+func TestModel_FeedChanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := newTestModel(t, mock_client.NewMockHttpClient(ctrl))
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = updated.(model)
+
+	stories := []*item.Item{{ID: 1, Titl: "one"}, {ID: 2, Titl: "two"}, {ID: 3, Titl: "three"}}
+	m.ids[0] = []int{1, 2, 3}
+	m.status[0] = tabStatus{loaded: true, ready: true}
+	m.loaded[0] = 3
+	m.TabContent[0].SetItems([]list.Item{stories[0], stories[1], stories[2]})
+	m.TabContent[0].Select(1)
+
+	// The feed shrinks and moves the selected story to the bottom.
+	assert.NotPanics(t, func() {
+		updated, _ = m.Update(feedMsg{hn.Feed{Type: constants.Items.TopItems, IDs: []int{4, 2}}})
+	})
+	m = updated.(model)
+
+	assert.Equal(t, []list.Item{skeleton{rank: 0}, stories[1]}, m.TabContent[0].Items())
+	assert.Equal(t, stories[1], m.TabContent[0].SelectedItem())
+	assert.Equal(t, []int{0}, m.pendingRanks(0))
+
+	// A story fetched for the previous feed doesn't land on the rank it had.
+	updated, _ = m.Update(storyMsg{tabID: 0, rank: 0, id: 1, item: stories[0]})
+	m = updated.(model)
+	assert.Equal(t, skeleton{rank: 0}, m.TabContent[0].Items()[0])
+
+	updated, _ = m.Update(storyMsg{tabID: 0, rank: 0, id: 4, item: &item.Item{ID: 4, Titl: "four"}})
+	m = updated.(model)
+	assert.Equal(t, &item.Item{ID: 4, Titl: "four"}, m.TabContent[0].Items()[0])
+}
+
+func TestModel_NextAfterResize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := newTestModel(t, mock_client.NewMockHttpClient(ctrl))
+
+	ids := make([]int, 100)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	updated, _ = updated.Update(idsMsg{tabID: 0, ids: ids})
+	m = updated.(model)
+
+	first := m.TabContent[0].Paginator.PerPage
+	require.Equal(t, first, m.loaded[0])
+
+	// The following page starts right after the loaded stories, whatever the new page size.
+	updated, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 40})
+	m = updated.(model)
+	require.NotEqual(t, first, m.TabContent[0].Paginator.PerPage)
+
+	m.next(0)
+
+	items := m.TabContent[0].Items()
+	require.Len(t, items, first+m.TabContent[0].Paginator.PerPage)
+	assert.Equal(t, len(items), m.loaded[0])
+
+	for rank, li := range items {
+		assert.Equal(t, skeleton{rank: rank}, li)
+	}
+}
+
+func TestModel_WindowSizeOnSearchTab(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := newTestModel(t, mock_client.NewMockHttpClient(ctrl))
+	m.activeTab = len(m.TabContent)
+
+	assert.NotPanics(t, func() {
+		updated, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+		_ = updated.View()
+	})
+}
+
+func TestModel_FetchPollFromSearch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mock_client.NewMockHttpClient(ctrl)
+	c.EXPECT().Get(gomock.Any(), "item/1.json").Return([]byte(`{"id":1,"type":"poll","parts":[2,3]}`), nil)
+	c.EXPECT().Get(gomock.Any(), "item/2.json").Return([]byte(`{"id":2,"type":"pollopt","text":"yes"}`), nil)
+	c.EXPECT().Get(gomock.Any(), "item/3.json").Return([]byte(`{"id":3,"type":"pollopt","text":"no"}`), nil)
+
+	m := newTestModel(t, c)
+
+	// Search hits don't carry the options of a poll.
+	poll := &item.Item{ID: 1, Type: "poll", Titl: "poll"}
+
+	msg, ok := m.fetchPoll(poll)().(pollMsg)
+	require.True(t, ok)
+
+	assert.Equal(t, poll, msg.poll)
+	require.Len(t, msg.options, 2)
+	assert.Equal(t, "yes", msg.options[0].Text)
+	assert.Equal(t, "no", msg.options[1].Text)
+}
+
+func TestModel_FeedChangedWithFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := newTestModel(t, mock_client.NewMockHttpClient(ctrl))
+	m.cfg.Tabs[0].Filter = "go"
+
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	updated, _ = updated.Update(idsMsg{tabID: 0, ids: []int{1, 2}})
+	m = updated.(model)
+
+	golang := &item.Item{ID: 1, Titl: "go"}
+	updated, _ = m.Update(storyMsg{tabID: 0, rank: 0, id: 1, item: golang})
+	updated, _ = updated.Update(storyMsg{tabID: 0, rank: 1, id: 2, item: &item.Item{ID: 2, Titl: "rust"}})
+	m = updated.(model)
+	require.Equal(t, []list.Item{golang}, m.TabContent[0].Items())
+
+	// The story removed by the filter is not fetched again.
+	updated, _ = m.Update(feedMsg{hn.Feed{Type: constants.Items.TopItems, IDs: []int{3, 2, 1}}})
+	m = updated.(model)
+
+	assert.Equal(t, []list.Item{skeleton{rank: 0}}, m.TabContent[0].Items())
+	assert.Equal(t, []int{0}, m.pendingRanks(0))
+}
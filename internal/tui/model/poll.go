@@ -0,0 +1,117 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/KarolosLykos/hackertea/internal/item"
+	"github.com/KarolosLykos/hackertea/internal/tui/keys"
+	"github.com/KarolosLykos/hackertea/internal/tui/theme"
+	"github.com/KarolosLykos/hackertea/internal/utils"
+)
+
+// pollBarPadding is the indentation of the bars below the options.
+const pollBarPadding = 2
+
+// pollView holds the state of the results screen of a poll.
+type pollView struct {
+	poll     *item.Item
+	options  []*item.Item
+	width    int
+	viewport viewport.Model
+	help     help.Model
+	keys     help.KeyMap
+}
+
+// newPollView returns an empty results screen for the given poll.
+func newPollView(poll *item.Item, width, height int) pollView {
+	p := pollView{
+		poll:     poll,
+		viewport: viewport.New(0, 0),
+		help:     help.New(),
+		keys:     keys.NewPollKeyMap(),
+	}
+
+	p.setSize(width, height)
+
+	return p
+}
+
+// setSize resizes the screen, keeping room for the help line.
+func (p *pollView) setSize(width, height int) {
+	p.width = width
+	p.viewport.Width = width
+	p.viewport.Height = utils.Max(height-2, 1)
+	p.help.Width = width
+}
+
+// setOptions replaces the options shown on the screen.
+func (p *pollView) setOptions(options []*item.Item, th *theme.Theme) {
+	p.options = options
+	p.render(th)
+	p.viewport.GotoTop()
+}
+
+// render writes the poll and a bar for each of its options to the viewport.
+func (p *pollView) render(th *theme.Theme) {
+	if p.poll == nil {
+		return
+	}
+
+	sections := []string{
+		th.NormalTitle.Copy().Width(p.width).Render(p.poll.Title()),
+		th.NormalDesc.Copy().Width(p.width).Render(p.poll.Description()),
+	}
+
+	if text := item.HTMLToText(p.poll.Text); text != "" {
+		sections = append(sections, "", th.NormalDesc.Copy().Width(p.width).Render(text))
+	}
+
+	sections = append(sections, "")
+
+	maxScore := 0
+	labels := make([]string, len(p.options))
+	labelWidth := 0
+
+	for n, option := range p.options {
+		maxScore = utils.Max(maxScore, option.Score)
+
+		labels[n] = th.NormalDesc.Copy().UnsetPadding().Render(" " + points(option.Score))
+		labelWidth = utils.Max(labelWidth, lipgloss.Width(labels[n]))
+	}
+
+	// The widest bar and its label fit on a single line.
+	barWidth := utils.Max(p.width-pollBarPadding-labelWidth, 1)
+
+	for n, option := range p.options {
+		length := 0
+		if maxScore > 0 {
+			length = option.Score * barWidth / maxScore
+		}
+
+		sections = append(
+			sections,
+			th.NormalTitle.Copy().Width(p.width).Render(item.HTMLToText(option.Text)),
+			th.Bar.Copy().PaddingLeft(pollBarPadding).Render(strings.Repeat("█", length))+labels[n],
+		)
+	}
+
+	p.viewport.SetContent(lipgloss.JoinVertical(lipgloss.Left, sections...))
+}
+
+func (p pollView) view() string {
+	return lipgloss.JoinVertical(lipgloss.Left, p.viewport.View(), "", p.help.View(p.keys))
+}
+
+// points returns the given score followed by "point" or "points".
+func points(score int) string {
+	if score == 1 {
+		return "1 point"
+	}
+
+	return fmt.Sprintf("%d points", score)
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KarolosLykos/hackertea/internal/item"
+	"github.com/KarolosLykos/hackertea/internal/tui/theme"
+)
+
+func TestPollView_Render(t *testing.T) {
+	th, err := theme.NewTheme()
+	require.NoError(t, err)
+
+	options := make([]*item.Item, 20)
+	for n := range options {
+		options[n] = &item.Item{ID: n + 2, Text: fmt.Sprintf("option %d", n), Score: n * 100}
+	}
+
+	options[0].Score = 1
+
+	p := newPollView(&item.Item{ID: 1, Titl: "poll"}, 40, 10)
+	p.setOptions(options, th)
+
+	view := p.viewport.View()
+	for _, line := range strings.Split(view, "\n") {
+		assert.LessOrEqual(t, lipgloss.Width(line), 40, "line %q should not wrap", line)
+	}
+
+	assert.Contains(t, view, " 1 point")
+	assert.NotContains(t, view, " 1 points")
+
+	// Polls longer than the screen scroll.
+	assert.Equal(t, 8, p.viewport.Height)
+	assert.False(t, p.viewport.AtBottom())
+
+	p.viewport.GotoBottom()
+	assert.Contains(t, p.viewport.View(), "1900 points")
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/KarolosLykos/hackertea/internal/hn"
+	"github.com/KarolosLykos/hackertea/internal/item"
+	"github.com/KarolosLykos/hackertea/internal/tui/theme"
+	"github.com/KarolosLykos/hackertea/internal/user"
+	"github.com/KarolosLykos/hackertea/internal/utils"
+)
+
+// profileHeaderHeight is the number of lines kept above the submissions list.
+const profileHeaderHeight = 6
+
+// profileView holds the state of the profile screen of a user.
+type profileView struct {
+	id   string
+	user *user.User
+	err  error
+	list list.Model
+}
+
+// newProfileView returns an empty profile screen for the given user ID.
+func newProfileView(id string, l list.Model, width, height int) profileView {
+	p := profileView{id: id, list: l}
+	p.setSize(width, height)
+
+	return p
+}
+
+// setSize resizes the screen, keeping room for the profile header.
+func (p *profileView) setSize(width, height int) {
+	p.list.SetSize(width, utils.Max(height-profileHeaderHeight, 1))
+}
+
+// hasMore reports whether there are submissions left to fetch.
+func (p profileView) hasMore() bool {
+	return p.user != nil && len(p.list.Items()) < len(p.user.Submitted)
+}
+
+// nextPage returns the bounds of the next page of submissions to fetch.
+func (p profileView) nextPage() (int, int) {
+	start := len(p.list.Items())
+
+	return start, utils.Min(start+p.list.Paginator.PerPage, len(p.user.Submitted))
+}
+
+func (p profileView) view(th *theme.Theme) string {
+	if errors.Is(p.err, hn.ErrNotFound) {
+		return th.NormalDesc.Render(fmt.Sprintf("There is no user named %s", p.id))
+	}
+
+	if p.err != nil {
+		return th.NormalDesc.Render(fmt.Sprintf("Could not get user %s (%s)", p.id, p.err.Error()))
+	}
+
+	about := item.HTMLToText(p.user.About)
+	if about == "" {
+		about = "No description."
+	}
+
+	header := lipgloss.JoinVertical(
+		lipgloss.Left,
+		th.NormalTitle.Render(p.user.ID),
+		th.NormalDesc.Render(fmt.Sprintf(
+			"%d karma, joined %s ago, %d submissions",
+			p.user.Karma,
+			p.user.Age(),
+			len(p.user.Submitted),
+		)),
+		"",
+		th.NormalDesc.Copy().MaxHeight(profileHeaderHeight-4).Width(p.list.Width()).Render(about),
+	)
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, "", p.list.View())
+}
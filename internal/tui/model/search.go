@@ -0,0 +1,64 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/KarolosLykos/hackertea/internal/search"
+	"github.com/KarolosLykos/hackertea/internal/tui/theme"
+	"github.com/KarolosLykos/hackertea/internal/utils"
+)
+
+// searchHeaderHeight is the number of lines kept above the results list.
+const searchHeaderHeight = 3
+
+// searchView holds the state of the search tab.
+type searchView struct {
+	input  textinput.Model
+	list   list.Model
+	query  search.Query
+	result *search.Result
+	err    error
+}
+
+// newSearchView returns an empty search tab with a focused query input.
+func newSearchView(l list.Model) searchView {
+	input := textinput.New()
+	input.Prompt = "Search: "
+	input.Placeholder = "rust tag:story author:pg points:100 after:2024-01-01 sort:date"
+	input.Focus()
+
+	return searchView{input: input, list: l}
+}
+
+// setSize resizes the tab, keeping room for the query input and the status line.
+func (s *searchView) setSize(width, height int) {
+	s.input.Width = utils.Max(width-lipgloss.Width(s.input.Prompt)-1, 1)
+	s.list.SetSize(width, utils.Max(height-searchHeaderHeight, 1))
+}
+
+// hasMore reports whether there are result pages left to fetch.
+func (s searchView) hasMore() bool {
+	return s.result != nil && s.result.Page+1 < s.result.NbPages
+}
+
+func (s searchView) view(th *theme.Theme) string {
+	status := "Type a query and press enter."
+	switch {
+	case s.err != nil:
+		status = fmt.Sprintf("Could not search (%s)", s.err.Error())
+	case s.result != nil:
+		status = fmt.Sprintf("%d results", s.result.Hits)
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		s.input.View(),
+		th.NormalDesc.Render(status),
+		"",
+		s.list.View(),
+	)
+}
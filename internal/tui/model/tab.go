@@ -0,0 +1,82 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/KarolosLykos/hackertea/internal/config"
+	"github.com/KarolosLykos/hackertea/internal/constants"
+	"github.com/KarolosLykos/hackertea/internal/hn"
+	"github.com/KarolosLykos/hackertea/internal/tui/theme"
+)
+
+// loadingIndicatorHeight is the number of lines kept below the stories for the loading indicator.
+const loadingIndicatorHeight = 1
+
+// skeleton is the row shown in place of a story that is still being fetched.
+type skeleton struct {
+	rank int
+}
+
+func (s skeleton) Title() string       { return fmt.Sprintf("%d. Loading…", s.rank+1) }
+func (s skeleton) Description() string { return "" }
+func (s skeleton) FilterValue() string { return "" }
+
+// tabStatus is the loading state of a story tab.
+type tabStatus struct {
+	// loaded is set once the story IDs of the tab are fetched.
+	loaded bool
+	// ready is set once the first page of stories is requested.
+	ready bool
+	// err is set if the story IDs could not be fetched.
+	err error
+}
+
+// pending reports whether the tab is still waiting for its story IDs.
+func (s tabStatus) pending() bool {
+	return s.err == nil && !s.ready
+}
+
+func (s tabStatus) view(th *theme.Theme, name string) string {
+	return th.NormalDesc.Render(fmt.Sprintf("Could not load %s (%s). Press r to retry.", name, s.err.Error()))
+}
+
+// loadFeed fetches the story IDs of the given tab.
+func loadFeed(ctx context.Context, client *hn.HN, tab config.Feed) ([]int, error) {
+	if tab.Source == string(constants.Items.User) {
+		u, err := client.GetUser(ctx, tab.User)
+		if err != nil {
+			return nil, err
+		}
+
+		return u.Submitted, nil
+	}
+
+	return client.GetItems(ctx, constants.ItemType(tab.Source))
+}
+
+// feedTypes returns the distinct feeds shown in the given tabs, leaving out the submissions of users.
+func feedTypes(tabs []config.Feed) []constants.ItemType {
+	var types []constants.ItemType
+	for _, t := range tabs {
+		it := constants.ItemType(t.Source)
+		if it != constants.Items.User && !slices.Contains(types, it) {
+			types = append(types, it)
+		}
+	}
+
+	return types
+}
+
+// tabView returns the stories of the active tab, with a loading indicator below while some are being fetched.
+func (m model) tabView() string {
+	indicator := ""
+	if m.loadingMore() {
+		indicator = m.theme.NormalDesc.Render(m.spinner.View() + " Loading stories…")
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, m.TabContent[m.activeTab].View(), indicator)
+}
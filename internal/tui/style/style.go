@@ -135,6 +135,18 @@ func VisitedStyle(light, dark string) lipgloss.Style {
 		Foreground(lipgloss.AdaptiveColor{Light: light, Dark: dark})
 }
 
+func BarStyle(light, dark string) lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: light, Dark: dark})
+}
+
+func DebugStyle(light, dark string) lipgloss.Style {
+	return lipgloss.NewStyle().
+		BorderForeground(lipgloss.AdaptiveColor{Light: light, Dark: dark}).
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1)
+}
+
 func FilterMatchedStyle(bLight, bDark, light, dark string) lipgloss.Style {
 	return lipgloss.NewStyle().
 		Background(lipgloss.AdaptiveColor{Light: bLight, Dark: bDark}).
@@ -377,6 +377,22 @@ func TestVisitedStyle(t *testing.T) {
 	assert.Equal(t, s, VisitedStyle("#FFFFFF", "#000000"))
 }
 
+func TestBarStyle(t *testing.T) {
+	s := lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#000000"})
+
+	assert.Equal(t, s, BarStyle("#FFFFFF", "#000000"))
+}
+
+func TestDebugStyle(t *testing.T) {
+	s := lipgloss.NewStyle().
+		BorderForeground(lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#000000"}).
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1)
+
+	assert.Equal(t, s, DebugStyle("#FFFFFF", "#000000"))
+}
+
 func TestFilterMatchedStyle(t *testing.T) {
 	s := lipgloss.NewStyle().
 		Background(lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#000000"}).
@@ -19,6 +19,8 @@ type Theme struct {
 	DimmedDesc    lipgloss.Style
 	FilterMatch   lipgloss.Style
 	Visited       lipgloss.Style
+	Bar           lipgloss.Style
+	Debug         lipgloss.Style
 	ActiveTab     lipgloss.Style
 	InActiveTab   lipgloss.Style
 	GapTab        lipgloss.Style
@@ -62,6 +64,8 @@ func NewTheme() (*Theme, error) {
 		DimmedTitle: style.ItemDimmedTitleStyle(cfg.Style.ListItem.DimmedTitle.Light, cfg.Style.ListItem.DimmedTitle.Dark),
 		DimmedDesc:  style.ItemDimmedDescStyle(cfg.Style.ListItem.DimmedDesc.Light, cfg.Style.ListItem.DimmedDesc.Dark),
 		Visited:     style.VisitedStyle(cfg.Style.Visited.Light, cfg.Style.Visited.Dark),
+		Bar:         style.BarStyle(cfg.Style.Tab.Color.Light, cfg.Style.Tab.Color.Dark),
+		Debug:       style.DebugStyle(cfg.Style.Window.Color.Light, cfg.Style.Window.Color.Dark),
 		FilterMatch: style.FilterMatchedStyle(
 			cfg.Style.ListItem.FilterMatch.BorderForeground.Light,
 			cfg.Style.ListItem.FilterMatch.BorderForeground.Dark,
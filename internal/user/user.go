@@ -0,0 +1,26 @@
+package user
+
+import (
+	"time"
+
+	"github.com/KarolosLykos/hackertea/internal/constants"
+)
+
+// User is a Hacker News user profile.
+type User struct {
+	ID        string `json:"id"`
+	Created   int    `json:"created"`
+	Karma     int    `json:"karma"`
+	About     string `json:"about"`
+	Submitted []int  `json:"submitted"`
+}
+
+// CreatedAt returns the time the account was created.
+func (u *User) CreatedAt() time.Time {
+	return time.Unix(int64(u.Created), 0)
+}
+
+// Age returns how long ago the account was created.
+func (u *User) Age() string {
+	return constants.CurrentTime.Sub(u.CreatedAt()).Round(time.Second).String()
+}
@@ -0,0 +1,20 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KarolosLykos/hackertea/internal/constants"
+)
+
+func TestUser_CreatedAt(t *testing.T) {
+	u := User{Created: int(time.Date(2021, 5, 25, 0, 0, 0, 0, time.Local).Unix())}
+	assert.Equal(t, time.Date(2021, 5, 25, 0, 0, 0, 0, time.Local), u.CreatedAt())
+}
+
+func TestUser_Age(t *testing.T) {
+	u := User{Created: int(constants.CurrentTime.Add(-time.Hour).Unix())}
+	assert.Equal(t, constants.CurrentTime.Sub(u.CreatedAt()).Round(time.Second).String(), u.Age())
+}
@@ -2,12 +2,14 @@ package utils
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os/exec"
-	"sync"
 
 	"github.com/charmbracelet/bubbles/list"
 
+	"github.com/KarolosLykos/hackertea/internal/client"
 	"github.com/KarolosLykos/hackertea/internal/constants"
 	"github.com/KarolosLykos/hackertea/internal/hn"
 	"github.com/KarolosLykos/hackertea/internal/item"
@@ -52,80 +54,152 @@ func Open(url string, runtimeOS string) error {
 	return err
 }
 
-// FetchStories fetches the given stories asynchronously from the Hacker News API,
-// using a pool of workers.
+// FetchStories fetches the given stories concurrently from the Hacker News API.
 // It returns a slice of list.Items that can be used to display the stories in a list.
 // The function takes the following parameters:
 // - ctx: The context to use for the API requests.
 // - client: The Hacker News client to use for the API requests.
 // - ids: A 2D slice containing the IDs of the stories to fetch for each tab.
-// - workers: The number of workers to use for fetching the stories.
 // - tabID: The index of the tab containing the IDs of the stories to fetch.
 // - start: The index of the first story to fetch.
-// - end: The index of the last story to fetch.
+// - end: The index of the last story to fetch. It is capped to the number of IDs of the tab.
 func FetchStories(
 	ctx context.Context,
 	client hn.Service,
 	ids [][]int,
-	workers, tabID, start, end int,
+	tabID, start, end int,
 ) []list.Item {
 	if tabID > len(ids)-1 {
 		return make([]list.Item, 0)
 	}
 
-	if end-start > len(ids[tabID]) || end > len(ids[tabID]) {
+	end = Min(end, len(ids[tabID]))
+	if start >= end {
 		return make([]list.Item, 0)
 	}
 
-	workers = Min(workers, end-start)
+	fetched, errs := client.GetItemsByIDs(ctx, ids[tabID][start:end])
 
-	type workReq struct {
-		id     int
-		number int
+	items := make([]list.Item, len(fetched))
+	for n, it := range fetched {
+		items[n] = story(it, errs[n])
 	}
-	type workResp struct {
-		item   *item.Item
-		number int
+
+	return items
+}
+
+// StreamStories fetches the given stories concurrently like FetchStories, but calls fn with every story
+// as soon as it arrives, along with its rank in the tab. fn is called from several goroutines at once.
+func StreamStories(
+	ctx context.Context,
+	client hn.Service,
+	ids [][]int,
+	tabID, start, end int,
+	fn func(rank int, story list.Item),
+) {
+	if tabID > len(ids)-1 {
+		return
 	}
 
-	work := make(chan workReq)
-	msg := make(chan workResp)
-
-	wg := sync.WaitGroup{}
-	for i := 0; i < workers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for s := range work {
-				it, err := client.GetItem(ctx, s.id)
-				if err != nil {
-					msg <- workResp{
-						item:   &item.Item{Titl: fmt.Sprintf("Could not get item (%s)", err.Error())},
-						number: s.number,
-					}
-				} else {
-					msg <- workResp{item: it, number: s.number}
-				}
-			}
-		}()
+	end = Min(end, len(ids[tabID]))
+	if start >= end {
+		return
 	}
 
-	go func() {
-		for n, s := range ids[tabID][start:end] {
-			work <- workReq{id: s, number: start + n}
+	client.GetItemsByIDsFunc(ctx, ids[tabID][start:end], func(n int, it *item.Item, err error) {
+		fn(start+n, story(it, err))
+	})
+}
+
+// story returns the given story, or a placeholder describing why it is not shown.
+func story(it *item.Item, err error) list.Item {
+	if err == nil {
+		err = hn.Removed(it)
+	}
+
+	if err != nil {
+		return &item.Item{Titl: missing(err)}
+	}
+
+	return it
+}
+
+// FetchComments fetches the discussion tree of the given story from the Hacker News API.
+// Each level of the tree is fetched concurrently,
+// and the replies of every comment keep the order of the item's Kids.
+// Comments that could not be fetched are replaced by a placeholder describing the error.
+func FetchComments(ctx context.Context, client hn.Service, story *item.Item) []*item.Comment {
+	root := &item.Comment{Item: story, Depth: -1}
+
+	for level := []*item.Comment{root}; len(level) > 0 && ctx.Err() == nil; {
+		var (
+			ids     []int
+			parents []*item.Comment
+		)
+
+		for _, c := range level {
+			for _, kid := range c.Kids {
+				ids = append(ids, kid)
+				parents = append(parents, c)
+			}
+		}
+
+		if len(ids) == 0 {
+			break
 		}
 
-		close(work)
+		fetched, errs := client.GetItemsByIDs(ctx, ids)
 
-		wg.Wait()
-		close(msg)
-	}()
+		next := make([]*item.Comment, 0, len(fetched))
+		for n, it := range fetched {
+			if errs[n] != nil {
+				it = &item.Item{ID: ids[n], Text: missing(errs[n])}
+			}
 
-	items := make([]list.Item, end-start)
+			c := &item.Comment{Item: it, Depth: parents[n].Depth + 1}
+			parents[n].Children = append(parents[n].Children, c)
+			next = append(next, c)
+		}
 
-	for result := range msg {
-		items[result.number-start] = result.item
+		level = next
 	}
 
-	return items
+	return root.Children
+}
+
+// FetchPollOptions fetches the options of the given poll from the Hacker News API,
+// concurrently and in the order of the poll's Parts.
+// Options that could not be fetched are replaced by a placeholder describing the error.
+func FetchPollOptions(ctx context.Context, client hn.Service, poll *item.Item) []*item.Item {
+	options, errs := client.GetItemsByIDs(ctx, poll.Parts)
+
+	for n, err := range errs {
+		if err != nil {
+			options[n] = &item.Item{ID: poll.Parts[n], Text: missing(err)}
+		}
+	}
+
+	return options
+}
+
+// missing describes why an item is not shown, given the error returned while fetching it.
+func missing(err error) string {
+	var se *client.StatusError
+
+	switch {
+	case errors.Is(err, hn.ErrNotFound):
+		return "Item not found"
+	case errors.Is(err, hn.ErrDeleted):
+		return "Item deleted"
+	case errors.Is(err, hn.ErrDead):
+		return "Item flagged as dead"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "Could not get item (timed out)"
+	case errors.As(err, &se) && se.Code == http.StatusTooManyRequests:
+		return "Could not get item (rate limited by the API)"
+	case errors.As(err, &se):
+		return fmt.Sprintf("Could not get item (the API answered %s)", se.Error())
+	default:
+		return fmt.Sprintf("Could not get item (%s)", err.Error())
+	}
 }
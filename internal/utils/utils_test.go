@@ -3,6 +3,8 @@ package utils
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"runtime"
 	"testing"
 
@@ -10,6 +12,8 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/KarolosLykos/hackertea/internal/client"
+	"github.com/KarolosLykos/hackertea/internal/hn"
 	"github.com/KarolosLykos/hackertea/internal/item"
 	mock_hn "github.com/KarolosLykos/hackertea/internal/mock/hn"
 )
@@ -63,7 +67,6 @@ func TestUtils_Open(t *testing.T) {
 func TestUtils_FetchStories(t *testing.T) {
 	tt := []struct {
 		name          string
-		workers       int
 		ids           [][]int
 		tabID         int
 		start, end    int
@@ -72,51 +75,37 @@ func TestUtils_FetchStories(t *testing.T) {
 		expectedLen   int
 	}{
 		{
-			name:    "fetch stories",
-			workers: 3,
-			ids:     [][]int{{1, 2, 3}},
-			tabID:   0, start: 0, end: 3,
+			name:  "fetch stories",
+			ids:   [][]int{{1, 2, 3}},
+			tabID: 0, start: 0, end: 3,
 			hnStub: func(hn *mock_hn.MockService) {
-				hn.EXPECT().GetItem(gomock.Any(), gomock.Any()).Return(&item.Item{ID: 1}, nil)
-				hn.EXPECT().GetItem(gomock.Any(), gomock.Any()).Return(&item.Item{ID: 2}, nil)
-				hn.EXPECT().GetItem(gomock.Any(), gomock.Any()).Return(&item.Item{ID: 3}, nil)
+				hn.EXPECT().GetItemsByIDs(gomock.Any(), []int{1, 2, 3}).Return(
+					[]*item.Item{{ID: 1}, {ID: 2}, {ID: 3}},
+					[]error{nil, nil, nil},
+				)
 			},
 			expectedItems: []list.Item{&item.Item{ID: 1}, &item.Item{ID: 2}, &item.Item{ID: 3}},
 			expectedLen:   3,
 		},
 		{
-			name:    "fetch stories with less workers",
-			workers: 1,
-			ids:     [][]int{{1, 2, 3}},
-			tabID:   0, start: 0, end: 3,
+			name:  "fetch a page of stories",
+			ids:   [][]int{{1, 2, 3}},
+			tabID: 0, start: 1, end: 2,
 			hnStub: func(hn *mock_hn.MockService) {
-				hn.EXPECT().GetItem(gomock.Any(), gomock.Any()).Return(&item.Item{ID: 1}, nil)
-				hn.EXPECT().GetItem(gomock.Any(), gomock.Any()).Return(&item.Item{ID: 2}, nil)
-				hn.EXPECT().GetItem(gomock.Any(), gomock.Any()).Return(&item.Item{ID: 3}, nil)
+				hn.EXPECT().GetItemsByIDs(gomock.Any(), []int{2}).Return([]*item.Item{{ID: 2}}, []error{nil})
 			},
-			expectedItems: []list.Item{&item.Item{ID: 1}, &item.Item{ID: 2}, &item.Item{ID: 3}},
-			expectedLen:   3,
-		},
-		{
-			name:    "fetch stories with unnecessary workers",
-			workers: 3,
-			ids:     [][]int{{1}},
-			tabID:   0, start: 0, end: 1,
-			hnStub: func(hn *mock_hn.MockService) {
-				hn.EXPECT().GetItem(gomock.Any(), gomock.Any()).Return(&item.Item{ID: 1}, nil)
-			},
-			expectedItems: []list.Item{&item.Item{ID: 1}},
+			expectedItems: []list.Item{&item.Item{ID: 2}},
 			expectedLen:   1,
 		},
 		{
-			name:    "fetch stories with error while getting item",
-			workers: 3,
-			ids:     [][]int{{1, 2, 3}},
-			tabID:   0, start: 0, end: 3,
+			name:  "fetch stories with error while getting item",
+			ids:   [][]int{{1, 2, 3}},
+			tabID: 0, start: 0, end: 3,
 			hnStub: func(hn *mock_hn.MockService) {
-				hn.EXPECT().GetItem(gomock.Any(), gomock.Any()).Return(&item.Item{ID: 1}, nil)
-				hn.EXPECT().GetItem(gomock.Any(), gomock.Any()).Return(nil, errors.New("error getting item"))
-				hn.EXPECT().GetItem(gomock.Any(), gomock.Any()).Return(&item.Item{ID: 3}, nil)
+				hn.EXPECT().GetItemsByIDs(gomock.Any(), []int{1, 2, 3}).Return(
+					[]*item.Item{{ID: 1}, nil, {ID: 3}},
+					[]error{nil, errors.New("error getting item"), nil},
+				)
 			},
 			expectedItems: []list.Item{
 				&item.Item{ID: 1},
@@ -126,23 +115,51 @@ func TestUtils_FetchStories(t *testing.T) {
 			expectedLen: 3,
 		},
 		{
-			name:    "wrong tabID",
-			workers: 3,
-			ids:     [][]int{{1}},
-			tabID:   1, start: 0, end: 1,
+			name:  "fetch stories that were removed",
+			ids:   [][]int{{1, 2, 3}},
+			tabID: 0, start: 0, end: 3,
+			hnStub: func(mock *mock_hn.MockService) {
+				mock.EXPECT().GetItemsByIDs(gomock.Any(), []int{1, 2, 3}).Return(
+					[]*item.Item{{ID: 1, Deleted: true}, {ID: 2, Dead: true}, nil},
+					[]error{nil, nil, fmt.Errorf("item 3: %w", hn.ErrNotFound)},
+				)
+			},
+			expectedItems: []list.Item{
+				&item.Item{Titl: "Item deleted"},
+				&item.Item{Titl: "Item flagged as dead"},
+				&item.Item{Titl: "Item not found"},
+			},
+			expectedLen: 3,
+		},
+		{
+			name:  "wrong tabID",
+			ids:   [][]int{{1}},
+			tabID: 1, start: 0, end: 1,
 			hnStub: func(hn *mock_hn.MockService) {
-				hn.EXPECT().GetItem(gomock.Any(), gomock.Any()).Times(0)
+				hn.EXPECT().GetItemsByIDs(gomock.Any(), gomock.Any()).Times(0)
 			},
 			expectedItems: []list.Item{},
 			expectedLen:   0,
 		},
 		{
-			name:    "wrong start - end",
-			workers: 3,
-			ids:     [][]int{{1}},
-			tabID:   0, start: 1, end: 2,
+			name:  "end past the last story",
+			ids:   [][]int{{1, 2}},
+			tabID: 0, start: 0, end: 5,
+			hnStub: func(hn *mock_hn.MockService) {
+				hn.EXPECT().GetItemsByIDs(gomock.Any(), []int{1, 2}).Return(
+					[]*item.Item{{ID: 1}, {ID: 2}},
+					[]error{nil, nil},
+				)
+			},
+			expectedItems: []list.Item{&item.Item{ID: 1}, &item.Item{ID: 2}},
+			expectedLen:   2,
+		},
+		{
+			name:  "wrong start - end",
+			ids:   [][]int{{1}},
+			tabID: 0, start: 1, end: 2,
 			hnStub: func(hn *mock_hn.MockService) {
-				hn.EXPECT().GetItem(gomock.Any(), gomock.Any()).Times(0)
+				hn.EXPECT().GetItemsByIDs(gomock.Any(), gomock.Any()).Times(0)
 			},
 			expectedItems: []list.Item{},
 			expectedLen:   0,
@@ -163,13 +180,103 @@ func TestUtils_FetchStories(t *testing.T) {
 				context.Background(),
 				mockHN,
 				tc.ids,
-				tc.workers,
 				tc.tabID,
 				tc.start, tc.end,
 			)
 
-			assert.ElementsMatch(t, items, tc.expectedItems)
+			assert.Equal(t, tc.expectedItems, items)
 			assert.Len(t, items, tc.expectedLen)
 		})
 	}
 }
+
+func TestUtils_StreamStories(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHN := mock_hn.NewMockService(ctrl)
+	mockHN.EXPECT().GetItemsByIDsFunc(gomock.Any(), []int{2, 3}, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ []int, fn func(int, *item.Item, error)) {
+			fn(1, &item.Item{ID: 3, Dead: true}, nil)
+			fn(0, &item.Item{ID: 2}, nil)
+		},
+	)
+
+	stories := map[int]list.Item{}
+	StreamStories(context.Background(), mockHN, [][]int{{1, 2, 3}}, 0, 1, 5, func(rank int, story list.Item) {
+		stories[rank] = story
+	})
+
+	assert.Equal(t, map[int]list.Item{
+		1: &item.Item{ID: 2},
+		2: &item.Item{Titl: "Item flagged as dead"},
+	}, stories)
+
+	// Nothing is fetched past the last story.
+	StreamStories(context.Background(), mockHN, [][]int{{1, 2, 3}}, 0, 3, 5, func(int, list.Item) {
+		t.Error("should not be called")
+	})
+}
+
+func TestUtils_FetchComments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHN := mock_hn.NewMockService(ctrl)
+	mockHN.EXPECT().GetItemsByIDs(gomock.Any(), []int{2, 3}).Return(
+		[]*item.Item{{ID: 2, Kids: []int{4}}, nil},
+		[]error{nil, errors.New("error getting item")},
+	)
+	mockHN.EXPECT().GetItemsByIDs(gomock.Any(), []int{4}).Return([]*item.Item{{ID: 4}}, []error{nil})
+
+	comments := FetchComments(context.Background(), mockHN, &item.Item{ID: 1, Kids: []int{2, 3}})
+
+	assert.Len(t, comments, 2)
+	assert.Equal(t, 2, comments[0].ID)
+	assert.Equal(t, 0, comments[0].Depth)
+	assert.Equal(t, 3, comments[1].ID)
+	assert.Equal(t, "Could not get item (error getting item)", comments[1].Text)
+	assert.Empty(t, comments[1].Children)
+
+	assert.Len(t, comments[0].Children, 1)
+	assert.Equal(t, 4, comments[0].Children[0].ID)
+	assert.Equal(t, 1, comments[0].Children[0].Depth)
+}
+
+func TestUtils_FetchPollOptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHN := mock_hn.NewMockService(ctrl)
+	mockHN.EXPECT().GetItemsByIDs(gomock.Any(), []int{2, 3, 4}).Return(
+		[]*item.Item{{ID: 2, Score: 10}, nil, {ID: 4, Score: 5}},
+		[]error{nil, errors.New("error getting item"), nil},
+	)
+
+	options := FetchPollOptions(context.Background(), mockHN, &item.Item{ID: 1, Parts: []int{2, 3, 4}})
+
+	assert.Equal(t, []*item.Item{
+		{ID: 2, Score: 10},
+		{ID: 3, Text: "Could not get item (error getting item)"},
+		{ID: 4, Score: 5},
+	}, options)
+}
+
+func TestUtils_Missing(t *testing.T) {
+	tt := []struct {
+		err      error
+		expected string
+	}{
+		{err: context.DeadlineExceeded, expected: "Could not get item (timed out)"},
+		{err: &client.StatusError{Code: http.StatusTooManyRequests}, expected: "Could not get item (rate limited by the API)"},
+		{
+			err:      &client.StatusError{Code: http.StatusBadGateway, Status: "502 Bad Gateway"},
+			expected: "Could not get item (the API answered 502 Bad Gateway)",
+		},
+		{err: errors.New("connection reset"), expected: "Could not get item (connection reset)"},
+	}
+
+	for _, tc := range tt {
+		assert.Equal(t, tc.expected, missing(tc.err))
+	}
+}
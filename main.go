@@ -3,28 +3,84 @@ package main
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
-	"time"
 
 	"github.com/KarolosLykos/hackertea/internal/cache"
 	"github.com/KarolosLykos/hackertea/internal/client"
+	"github.com/KarolosLykos/hackertea/internal/config"
 	"github.com/KarolosLykos/hackertea/internal/constants"
 	"github.com/KarolosLykos/hackertea/internal/hn"
+	"github.com/KarolosLykos/hackertea/internal/search"
 	"github.com/KarolosLykos/hackertea/internal/tui/model"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	ctx := context.Background()
+	// Every fetch still in flight is canceled on shutdown.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	c := client.New(constants.BaseURL, &http.Client{Timeout: 10 * time.Second})
-	memCache := cache.New()
-	hnClient := hn.New(c, memCache)
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Println("Error loading config: ", err)
+		os.Exit(1)
+	}
+
+	metrics := client.NewMetrics()
+
+	logger, closeLog, err := openLog(cfg.HTTP.LogFile)
+	if err != nil {
+		fmt.Println("Error opening log file: ", err)
+		os.Exit(1)
+	}
+	defer closeLog()
+
+	// The timeout is applied to every attempt by the middleware, so that retries get one each.
+	// The transport still gives up on responses whose headers never arrive.
+	httpClient := &http.Client{Transport: client.NewTransport(cfg.Workers)}
+
+	// Every worker and poller goes through the same client, and so shares its limiter.
+	// The search API is limited on its own.
+	c := client.New(constants.BaseURL, httpClient, client.WithMiddleware(
+		middleware(cfg, metrics, logger, client.NewLimiter(cfg.RateLimit.RPS, cfg.RateLimit.Burst))...,
+	))
+	// Items and feeds are kept on disk between sessions if enabled, falling back to memory only
+	// if the disk cache is unavailable.
+	var diskCache *cache.DiskCache
+	if cfg.Cache.Disk {
+		diskCache, _ = cache.NewDisk(cache.DefaultDiskDir(), cfg.Cache.DiskMaxAge, cfg.Cache.DiskMaxBytes)
+	}
+
+	// The entries not written yet are written on exit.
+	defer diskCache.Close()
+
+	itemCache, err := newCache(cfg.Cache, diskCache)
+	if err != nil {
+		fmt.Println("Error creating cache: ", err)
+		cancel()
+		closeLog()
+		diskCache.Close()
+		os.Exit(1)
+	}
+
+	opts := []hn.Option{hn.WithWorkers(cfg.Workers)}
+	if diskCache != nil {
+		opts = append(opts, hn.WithFeedCache(diskCache))
+	}
+
+	hnClient := hn.New(c, itemCache, opts...)
+	searcher := search.New(client.New(constants.SearchURL, httpClient, client.WithMiddleware(
+		middleware(cfg, metrics, logger, client.NewLimiter(cfg.RateLimit.RPS, cfg.RateLimit.Burst))...,
+	)))
 
-	m, err := model.New(ctx, hnClient)
+	m, err := model.New(ctx, cfg, hnClient, searcher, metrics)
 	if err != nil {
 		fmt.Println("Error creating model: ", err)
+		cancel()
+		closeLog()
+		diskCache.Close()
 		os.Exit(1)
 	}
 
@@ -32,6 +88,92 @@ func main() {
 
 	if _, err = p.Run(); err != nil {
 		fmt.Println("Error running program: ", err)
+		cancel()
+		closeLog()
+		diskCache.Close()
 		os.Exit(1)
 	}
 }
+
+// middleware returns the middleware enabled in the configuration, in the configured order,
+// waiting for the given limiter. Every request is recorded by the given metrics, which feed the debug overlay,
+// and logged by logger, if any.
+func middleware(
+	cfg *config.Config,
+	metrics *client.Metrics,
+	logger *slog.Logger,
+	limiter *client.Limiter,
+) []client.Middleware {
+	var mws []client.Middleware
+
+	for _, name := range cfg.HTTP.Middleware {
+		switch name {
+		case config.MiddlewareRetry:
+			if cfg.Retry.Max > 0 {
+				mws = append(mws, client.Retry(client.RetryPolicy{
+					MaxRetries: cfg.Retry.Max,
+					BaseDelay:  cfg.Retry.BaseDelay,
+					MaxDelay:   cfg.Retry.MaxDelay,
+				}))
+			}
+		case config.MiddlewareRateLimit:
+			if limiter != nil {
+				mws = append(mws, client.RateLimit(limiter))
+			}
+		case config.MiddlewareTimeout:
+			if cfg.HTTP.Timeout > 0 {
+				mws = append(mws, client.Timeout(cfg.HTTP.Timeout))
+			}
+		case config.MiddlewareMetrics:
+			mws = append(mws, metrics.Middleware())
+		case config.MiddlewareUserAgent:
+			if cfg.HTTP.UserAgent != "" {
+				mws = append(mws, client.UserAgent(cfg.HTTP.UserAgent))
+			}
+		case config.MiddlewareLogging:
+			if logger != nil {
+				mws = append(mws, client.Logging(logger))
+			}
+		case config.MiddlewareFaultInjection:
+			if cfg.HTTP.FaultRate > 0 {
+				mws = append(mws, client.FaultInjection(cfg.HTTP.FaultRate))
+			}
+		}
+	}
+
+	return mws
+}
+
+// openLog returns a logger writing to the given file, if any, along with a function closing it.
+func openLog(path string) (*slog.Logger, func(), error) {
+	if path == "" {
+		return nil, func() {}, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return slog.New(slog.NewTextHandler(f, nil)), func() { _ = f.Close() }, nil
+}
+
+// newCache returns the item cache selected in the configuration, in front of the given disk cache, if any.
+func newCache(cfg config.Cache, diskCache *cache.DiskCache) (cache.Cache, error) {
+	var c cache.Cache
+
+	switch cfg.Type {
+	case "memory":
+		c = cache.New()
+	case "lru", "":
+		c = cache.NewLRU(cfg.MaxEntries, cfg.MaxBytes)
+	default:
+		return nil, fmt.Errorf("unknown cache type %q", cfg.Type)
+	}
+
+	if diskCache == nil {
+		return c, nil
+	}
+
+	return cache.NewLayered(c, diskCache), nil
+}